@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 
 	monitorism "github.com/ethereum-optimism/monitorism/op-monitorism"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/balances"
@@ -15,6 +17,7 @@ import (
 	"github.com/ethereum-optimism/monitorism/op-monitorism/secrets"
 	"github.com/ethereum-optimism/monitorism/op-monitorism/withdrawals"
 	"github.com/ethereum-optimism/optimism/op-service/cliapp"
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 
@@ -49,6 +52,50 @@ func newCli(GitCommit string, GitDate string) *cli.App {
 				Description: "Monitors output roots posted on L1 against L2",
 				Flags:       append(fault.CLIFlags("FAULT_MON"), defaultFlags...),
 				Action:      cliapp.LifecycleCmd(FaultMain),
+				Subcommands: []*cli.Command{
+					{
+						Name:        "verify_range",
+						Usage:       "Validates the output roots of a range of output indexes once and exits",
+						Description: "Validates the output roots of a range of output indexes once, printing a JSON summary and exiting non-zero on any mismatch",
+						Flags:       append(fault.VerifyRangeCLIFlags("FAULT_MON"), defaultFlags...),
+						Action:      FaultVerifyRangeMain,
+					},
+					{
+						Name:        "find_earliest_mismatch",
+						Usage:       "Scans backwards from a mismatched output index for the earliest mismatch and exits",
+						Description: "Scans backwards from a mismatched output index, printing a JSON summary of the contiguous range of mismatched outputs ending at it",
+						Flags:       append(fault.FindEarliestMismatchCLIFlags("FAULT_MON"), defaultFlags...),
+						Action:      FaultFindEarliestMismatchMain,
+					},
+					{
+						Name:        "scan_history",
+						Usage:       "Validates every posted output root once, reporting all mismatches, and exits",
+						Description: "Validates the output roots of every output posted so far once, appending each mismatch to a JSONL report and printing a JSON summary, exiting non-zero on any mismatch. Resumable with --checkpoint.path",
+						Flags:       append(fault.ScanHistoryCLIFlags("FAULT_MON"), defaultFlags...),
+						Action:      FaultScanHistoryMain,
+					},
+					{
+						Name:        "output_root",
+						Usage:       "Reconstructs the output root of an L2 block and exits",
+						Description: "Reconstructs the output root of an L2 block from the L2 node alone, printing it as JSON along with its components",
+						Flags:       append(fault.OutputRootCLIFlags("FAULT_MON"), defaultFlags...),
+						Action:      FaultOutputRootMain,
+					},
+					{
+						Name:        "compare_oracles",
+						Usage:       "Compares the outputs of two L2OutputOracles over a range of output indexes and exits",
+						Description: "Compares the outputs of two L2OutputOracles over a range of output indexes, such as before and after a migration, printing a JSON summary and exiting non-zero on any divergence",
+						Flags:       append(fault.CompareOraclesCLIFlags("FAULT_MON"), defaultFlags...),
+						Action:      FaultCompareOraclesMain,
+					},
+					{
+						Name:        "verify_latest_finalized",
+						Usage:       "Validates the output root of the latest finalized output once and exits",
+						Description: "Validates the output root of the most recent output past its finalization window, printing a JSON result and exiting non-zero on a mismatch, for blackbox probes",
+						Flags:       append(fault.CLIFlags("FAULT_MON"), defaultFlags...),
+						Action:      FaultVerifyLatestFinalizedMain,
+					},
+				},
 			},
 			{
 				Name:        "withdrawals",
@@ -166,6 +213,17 @@ func FaultMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifec
 	}
 
 	metricsRegistry := opmetrics.NewRegistry()
+	if err := fault.RegisterGoCollector(metricsRegistry, cfg); err != nil {
+		return nil, fmt.Errorf("failed to register go collector: %w", err)
+	}
+	if len(cfg.Chains) > 0 {
+		monitor, err := fault.NewMultiMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create multi-chain fault monitor: %w", err)
+		}
+		return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
+	}
+
 	monitor, err := fault.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fault monitor: %w", err)
@@ -174,6 +232,168 @@ func FaultMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifec
 	return monitorism.NewCliApp(ctx, log, metricsRegistry, monitor)
 }
 
+func FaultVerifyRangeMain(ctx *cli.Context) error {
+	monitor, cleanup, err := newOneShotFaultMonitor(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	summary, err := monitor.VerifyRange(ctx.Context, uint64(ctx.Int64(fault.StartOutputIndexFlagName)), ctx.Uint64(fault.EndOutputIndexFlagName))
+	if err != nil {
+		return fmt.Errorf("failed to verify range: %w", err)
+	}
+	if err := json.NewEncoder(ctx.App.Writer).Encode(summary); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	if summary.Mismatches > 0 {
+		return fmt.Errorf("found %d mismatched outputs", summary.Mismatches)
+	}
+	return nil
+}
+
+func FaultFindEarliestMismatchMain(ctx *cli.Context) error {
+	monitor, cleanup, err := newOneShotFaultMonitor(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	mismatches, err := monitor.FindEarliestMismatch(ctx.Context, uint64(ctx.Int64(fault.StartOutputIndexFlagName)), ctx.Uint64(fault.MinOutputIndexFlagName))
+	if err != nil {
+		return fmt.Errorf("failed to find earliest mismatch: %w", err)
+	}
+	if err := json.NewEncoder(ctx.App.Writer).Encode(mismatches); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+func FaultScanHistoryMain(ctx *cli.Context) error {
+	monitor, cleanup, err := newOneShotFaultMonitor(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	scan, err := monitor.ScanHistory(ctx.Context, ctx.String(fault.ReportPathFlagName))
+	if err != nil {
+		return fmt.Errorf("failed to scan history: %w", err)
+	}
+	if err := json.NewEncoder(ctx.App.Writer).Encode(scan); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	if scan.Mismatches > 0 {
+		return fmt.Errorf("found %d mismatched outputs", scan.Mismatches)
+	}
+	return nil
+}
+
+func FaultVerifyLatestFinalizedMain(ctx *cli.Context) error {
+	monitor, cleanup, err := newOneShotFaultMonitor(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	result, err := monitor.VerifyLatestFinalized(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to verify latest finalized output: %w", err)
+	}
+	if err := json.NewEncoder(ctx.App.Writer).Encode(result); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	if result.Mismatched {
+		return fmt.Errorf("latest finalized output %d is mismatched", result.Index)
+	}
+	return nil
+}
+
+func FaultOutputRootMain(ctx *cli.Context) error {
+	cfg, err := fault.ReadOutputRootCLIFlags(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to parse output root config from flags: %w", err)
+	}
+
+	outputRoot, err := fault.ReconstructOutputRootFromConfig(ctx.Context, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to reconstruct output root: %w", err)
+	}
+	if err := json.NewEncoder(ctx.App.Writer).Encode(outputRoot); err != nil {
+		return fmt.Errorf("failed to write output root: %w", err)
+	}
+	return nil
+}
+
+func FaultCompareOraclesMain(ctx *cli.Context) error {
+	cfg, err := fault.ReadCompareOraclesCLIFlags(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to parse compare oracles config from flags: %w", err)
+	}
+
+	comparison, err := fault.CompareOraclesFromConfig(ctx.Context, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to compare oracles: %w", err)
+	}
+	if err := json.NewEncoder(ctx.App.Writer).Encode(comparison); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	if len(comparison.Divergences) > 0 {
+		return fmt.Errorf("found %d diverging outputs", len(comparison.Divergences))
+	}
+	return nil
+}
+
+// newOneShotFaultMonitor creates the fault monitor of a command checking specific outputs before
+// exiting, from --start.output.index if required. Logs are written to stderr, leaving stdout to
+// the summary.
+func newOneShotFaultMonitor(ctx *cli.Context, startRequired bool) (*fault.Monitor, func(), error) {
+	log := oplog.NewLogger(os.Stderr, oplog.ReadCLIConfig(ctx))
+	cfg, err := fault.ReadCLIFlags(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse fault config from flags: %w", err)
+	}
+	if len(cfg.Chains) > 0 {
+		return nil, nil, fmt.Errorf("--%s is not supported by this command", fault.ChainsConfigFlagName)
+	}
+	if startRequired && cfg.StartOutputIndex < 0 {
+		return nil, nil, fmt.Errorf("--%s must be set", fault.StartOutputIndexFlagName)
+	}
+	if !startRequired {
+		// outputs are found by the command, skipping the search on startup
+		cfg.StartOutputIndex = 0
+		cfg.StartFromLatest = false
+	}
+	// checked outputs are explicit, newly proposed ones are not followed
+	cfg.L1WSURL = ""
+
+	metricsRegistry := opmetrics.NewRegistry()
+	metricsCfg := opmetrics.ReadCLIConfig(ctx)
+	var metricsSrv *httputil.HTTPServer
+	if metricsCfg.Enabled {
+		metricsSrv, err = opmetrics.StartServer(metricsRegistry, metricsCfg.ListenAddr, metricsCfg.ListenPort)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to start metrics server: %w", err)
+		}
+	}
+
+	monitor, err := fault.NewMonitor(ctx.Context, log, opmetrics.With(metricsRegistry), cfg)
+	if err != nil {
+		if metricsSrv != nil {
+			_ = metricsSrv.Close()
+		}
+		return nil, nil, fmt.Errorf("failed to create fault monitor: %w", err)
+	}
+
+	cleanup := func() {
+		_ = monitor.Close(ctx.Context)
+		if metricsSrv != nil {
+			_ = metricsSrv.Close()
+		}
+	}
+	return monitor, cleanup, nil
+}
+
 func WithdrawalsMain(ctx *cli.Context, closeApp context.CancelCauseFunc) (cliapp.Lifecycle, error) {
 	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx))
 	cfg, err := withdrawals.ReadCLIFlags(ctx)
@@ -0,0 +1,49 @@
+package fault
+
+import (
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// alertKey identifies a mismatch alert. A different expected root at the same index, such
+// as after an l2 reorg, is alerted of anew.
+type alertKey struct {
+	index        uint64
+	expectedRoot common.Hash
+}
+
+// alertDedup suppresses repeated alerts of the same mismatch, firing again once the window
+// elapses, or never while the mismatch persists if the window is zero
+type alertDedup struct {
+	clock  clock.Clock
+	window time.Duration
+
+	lastSent map[alertKey]time.Time
+}
+
+func newAlertDedup(clk clock.Clock, window time.Duration) *alertDedup {
+	return &alertDedup{clock: clk, window: window, lastSent: make(map[alertKey]time.Time)}
+}
+
+// allow returns true if the mismatch should be alerted of, recording it as sent
+func (d *alertDedup) allow(index uint64, expectedRoot common.Hash) bool {
+	key := alertKey{index: index, expectedRoot: expectedRoot}
+	now := d.clock.Now()
+	if last, ok := d.lastSent[key]; ok && (d.window == 0 || now.Sub(last) < d.window) {
+		return false
+	}
+	d.lastSent[key] = now
+	return true
+}
+
+// clear forgets the alerts of the index once its mismatch cleared, alerting of it
+// immediately should it mismatch again
+func (d *alertDedup) clear(index uint64) {
+	for key := range d.lastSent {
+		if key.index == index {
+			delete(d.lastSent, key)
+		}
+	}
+}
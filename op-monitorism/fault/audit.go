@@ -0,0 +1,72 @@
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	auditResultPass = "pass"
+	auditResultFail = "fail"
+)
+
+// auditRecord is a line of the audit log, recording the result of checking an output
+type auditRecord struct {
+	Index              uint64      `json:"index"`
+	L2BlockNumber      uint64      `json:"l2_block_number"`
+	OutputRoot         common.Hash `json:"output_root"`
+	ExpectedOutputRoot common.Hash `json:"expected_output_root"`
+	Timestamp          time.Time   `json:"timestamp"`
+	Result             string      `json:"result"`
+}
+
+// auditLog appends a JSONL record of every checked output to a file. Every record is
+// synced to disk before returning, so records survive a crash of the monitor.
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{file: file}, nil
+}
+
+func (a *auditLog) record(check *outputCheck) error {
+	result := auditResultPass
+	if check.mismatched() {
+		result = auditResultFail
+	}
+	line, err := json.Marshal(auditRecord{
+		Index:              check.index,
+		L2BlockNumber:      check.output.L2BlockNumber.Uint64(),
+		OutputRoot:         check.output.OutputRoot,
+		ExpectedOutputRoot: common.Hash(check.outputRoot),
+		Timestamp:          time.Now().UTC(),
+		Result:             result,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return a.file.Sync()
+}
+
+func (a *auditLog) close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
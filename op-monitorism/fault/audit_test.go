@@ -0,0 +1,49 @@
+package fault
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	audit, err := openAuditLog(path)
+	require.NoError(t, err)
+	output := bindings.TypesOutputProposal{OutputRoot: [32]byte{1}, L2BlockNumber: big.NewInt(100)}
+	require.NoError(t, audit.record(&outputCheck{index: 1, output: output, outputRoot: eth.Bytes32{1}, valid: true}))
+	require.NoError(t, audit.record(&outputCheck{index: 2, output: output, outputRoot: eth.Bytes32{2}}))
+	require.NoError(t, audit.close())
+
+	// reopening appends to the existing records
+	audit, err = openAuditLog(path)
+	require.NoError(t, err)
+	require.NoError(t, audit.record(&outputCheck{index: 3, output: output, outputRoot: eth.Bytes32{1}, valid: true}))
+	require.NoError(t, audit.close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record auditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, records, 3)
+	require.Equal(t, auditResultPass, records[0].Result)
+	require.Equal(t, auditResultFail, records[1].Result)
+	require.Equal(t, uint64(3), records[2].Index)
+	require.Equal(t, uint64(100), records[2].L2BlockNumber)
+}
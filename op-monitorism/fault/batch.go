@@ -0,0 +1,260 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// batchError is a failure of the batch request as a whole, as opposed to one of its calls
+type batchError struct {
+	err error
+}
+
+func (e *batchError) Error() string { return "batch request failed: " + e.err.Error() }
+func (e *batchError) Unwrap() error { return e.err }
+
+// storageProof is the part of the eth_getProof response used for reconstruction. The
+// storage hash is unset if missing from the response
+type storageProof struct {
+	StorageHash  *common.Hash
+	AccountProof []hexutil.Bytes
+}
+
+// batchL2 sends the calls to the l2 node in a single batch request, retrying on failure.
+// The first error of the calls is returned if any failed
+func (m *Monitor) batchL2(ctx context.Context, section string, elems []rpc.BatchElem) error {
+	_, err := withRetries(ctx, m, "l2", section, func(ctx context.Context) (struct{}, error) {
+		if err := m.l2Client.Client().BatchCallContext(ctx, elems); err != nil {
+			return struct{}{}, &batchError{err}
+		}
+		for _, elem := range elems {
+			if elem.Error != nil {
+				return struct{}{}, elem.Error
+			}
+		}
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// fallBackFromBatch falls back to sequential requests after a failed batch request. Batching
+// is disabled once the sequential requests succeed, as the node then most likely does not
+// support batch requests
+func (m *Monitor) fallBackFromBatch(err error, sequential func() error) error {
+	m.log.Warn("l2 batch request failed, falling back to sequential requests", "err", err)
+	if err := sequential(); err != nil {
+		return err
+	}
+	if m.batchRPC.CompareAndSwap(true, false) {
+		m.log.Warn("l2 node does not support batch requests, disabling batching")
+	}
+	return nil
+}
+
+// l2HeightAndBlock fetches the latest l2 height and the block at the given number. The
+// block is nil if the node has not yet synced up to it
+func (m *Monitor) l2HeightAndBlock(ctx context.Context, number *big.Int) (uint64, *types.Block, error) {
+	if !m.batchRPC.Load() {
+		return m.l2HeightAndBlockSequential(ctx, number)
+	}
+
+	var height hexutil.Uint64
+	var header *types.Header
+	err := m.batchL2(ctx, "batchBlock", []rpc.BatchElem{
+		{Method: "eth_blockNumber", Result: &height},
+		{Method: "eth_getBlockByNumber", Args: []any{hexutil.EncodeBig(number), false}, Result: &header},
+	})
+	var batchErr *batchError
+	switch {
+	case errors.As(err, &batchErr) && ctx.Err() == nil:
+		var seqHeight uint64
+		var block *types.Block
+		err = m.fallBackFromBatch(err, func() error {
+			var err error
+			seqHeight, block, err = m.l2HeightAndBlockSequential(ctx, number)
+			return err
+		})
+		return seqHeight, block, err
+	case err != nil:
+		if ctx.Err() != nil {
+			return 0, nil, ctx.Err()
+		}
+		m.log.Error("failed to query l2 height and block", "height", number, "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "batchBlock").Inc()
+		return 0, nil, err
+	}
+
+	if uint64(height) < number.Uint64() {
+		return uint64(height), nil, nil
+	}
+	if header == nil {
+		m.log.Error("failed to query l2 block", "height", number, "err", ethereum.NotFound)
+		m.nodeConnectionFailures.WithLabelValues("l2", "batchBlock").Inc()
+		return 0, nil, ethereum.NotFound
+	}
+	return uint64(height), types.NewBlockWithHeader(header), nil
+}
+
+func (m *Monitor) l2HeightAndBlockSequential(ctx context.Context, number *big.Int) (uint64, *types.Block, error) {
+	height, err := withRetries(ctx, m, "l2", "blockNumber", func(ctx context.Context) (uint64, error) {
+		return m.l2Client.BlockNumber(ctx)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, nil, ctx.Err()
+		}
+		m.log.Error("failed to query latest l2 height", "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "blockNumber").Inc()
+		return 0, nil, err
+	}
+	if height < number.Uint64() {
+		return height, nil, nil
+	}
+	if m.fetchBlockByHash {
+		block, err := m.l2BlockByHash(ctx, number)
+		return height, block, err
+	}
+
+	block, err := withRetries(ctx, m, "l2", "blockByNumber", func(ctx context.Context) (*types.Block, error) {
+		return m.l2Client.BlockByNumber(ctx, number)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, nil, ctx.Err()
+		}
+		m.log.Error("failed to query l2 block", "height", number, "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "blockByNumber").Inc()
+		return 0, nil, err
+	}
+	return height, block, nil
+}
+
+// l2BlockByHash fetches the l2 block at the number by the hash of the header at that number,
+// which some providers serve faster than blocks by number. The block is consistent with the
+// header even if reorged in between, failing with errReconstructionRace if no longer served.
+func (m *Monitor) l2BlockByHash(ctx context.Context, number *big.Int) (*types.Block, error) {
+	header, err := withRetries(ctx, m, "l2", "headerByNumber", func(ctx context.Context) (*types.Header, error) {
+		return m.l2Client.HeaderByNumber(ctx, number)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		m.log.Error("failed to query l2 header", "height", number, "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "headerByNumber").Inc()
+		return nil, err
+	}
+
+	block, err := withRetries(ctx, m, "l2", "blockByHash", func(ctx context.Context) (*types.Block, error) {
+		return m.l2Client.BlockByHash(ctx, header.Hash())
+	})
+	switch {
+	case errors.Is(err, ethereum.NotFound):
+		m.log.Warn("l2 block changed during reconstruction, skipping", "height", number, "block_hash", header.Hash().String())
+		m.reconstructionRaces.Inc()
+		return nil, errReconstructionRace
+	case err != nil:
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		m.log.Error("failed to query l2 block by hash", "height", number, "block_hash", header.Hash().String(), "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "blockByHash").Inc()
+		return nil, err
+	}
+	return block, nil
+}
+
+// l2ProofAndHeader fetches the storage proof of the message passer at the block, along with
+// the canonical header at the block's number. Unless proofs are fetched by number, the proof
+// is pinned to the block's hash and the node is required to confirm the block is canonical,
+// failing with errReconstructionRace otherwise.
+func (m *Monitor) l2ProofAndHeader(ctx context.Context, block *types.Block) (storageProof, *types.Header, error) {
+	proofBlock := rpc.BlockNumberOrHashWithHash(block.Hash(), true)
+	if m.proofByNumber {
+		proofBlock = rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block.Number().Int64()))
+	}
+	if !m.batchRPC.Load() {
+		return m.l2ProofAndHeaderSequential(ctx, block, proofBlock)
+	}
+
+	var proof storageProof
+	var header *types.Header
+	err := m.batchL2(ctx, "batchProof", []rpc.BatchElem{
+		{Method: "eth_getProof", Args: []any{m.messagePasserAddress, nil, proofBlock}, Result: &proof},
+		{Method: "eth_getBlockByNumber", Args: []any{hexutil.EncodeBig(block.Number()), false}, Result: &header},
+	})
+	var batchErr *batchError
+	switch {
+	case errors.As(err, &batchErr) && ctx.Err() == nil:
+		err = m.fallBackFromBatch(err, func() error {
+			var err error
+			proof, header, err = m.l2ProofAndHeaderSequential(ctx, block, proofBlock)
+			return err
+		})
+		return proof, header, err
+	case err != nil:
+		if ctx.Err() != nil {
+			return storageProof{}, nil, ctx.Err()
+		}
+		if isNotCanonical(err) {
+			return storageProof{}, nil, m.proofNotCanonical(block, err)
+		}
+		m.log.Error("failed to query l2 proof and header", "height", block.Number(), "address", m.messagePasserAddress.String(), "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "batchProof").Inc()
+		return storageProof{}, nil, err
+	}
+
+	if header == nil {
+		m.log.Error("failed to query l2 header", "height", block.Number(), "err", ethereum.NotFound)
+		m.nodeConnectionFailures.WithLabelValues("l2", "batchProof").Inc()
+		return storageProof{}, nil, ethereum.NotFound
+	}
+	return proof, header, nil
+}
+
+func (m *Monitor) l2ProofAndHeaderSequential(ctx context.Context, block *types.Block, proofBlock rpc.BlockNumberOrHash) (storageProof, *types.Header, error) {
+	proof, err := withRetries(ctx, m, "l2", "getProof", func(ctx context.Context) (storageProof, error) {
+		var proof storageProof
+		err := m.l2Client.Client().CallContext(ctx, &proof, "eth_getProof", m.messagePasserAddress, nil, proofBlock)
+		return proof, err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return storageProof{}, nil, ctx.Err()
+		}
+		if isNotCanonical(err) {
+			return storageProof{}, nil, m.proofNotCanonical(block, err)
+		}
+		m.log.Error("failed to query for proof response of l2ToL1MP contract", "address", m.messagePasserAddress.String(), "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "getProof").Inc()
+		return storageProof{}, nil, err
+	}
+
+	header, err := withRetries(ctx, m, "l2", "headerByNumber", func(ctx context.Context) (*types.Header, error) {
+		return m.l2Client.HeaderByNumber(ctx, block.Number())
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return storageProof{}, nil, ctx.Err()
+		}
+		m.log.Error("failed to query l2 header", "height", block.Number(), "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "headerByNumber").Inc()
+		return storageProof{}, nil, err
+	}
+	return proof, header, nil
+}
+
+// proofNotCanonical records the node rejecting the proof of a block reorged out since it
+// was fetched, which is skipped as a reconstruction race rather than a fault
+func (m *Monitor) proofNotCanonical(block *types.Block, err error) error {
+	m.log.Warn("l2 block is no longer canonical, skipping", "height", block.Number(), "block_hash", block.Hash().String(), "err", err)
+	m.reconstructionRaces.Inc()
+	return errReconstructionRace
+}
@@ -0,0 +1,185 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type testRPCRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// newTestL2Server serves the l2 height and blocks from a fixed header. Batch requests
+// are rejected if unsupported, with the number of batches served counted
+func newTestL2Server(t *testing.T, header *types.Header, batchSupported bool, batches *int) *httptest.Server {
+	respond := func(req testRPCRequest) map[string]any {
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_blockNumber":
+			resp["result"] = hexutil.Uint64(header.Number.Uint64())
+		case "eth_getBlockByNumber", "eth_getBlockByHash":
+			resp["result"] = header
+		default:
+			resp["error"] = map[string]any{"code": -32601, "message": "method not found"}
+		}
+		return resp
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+
+		if body[0] != '[' {
+			var req testRPCRequest
+			require.NoError(t, json.Unmarshal(body, &req))
+			require.NoError(t, json.NewEncoder(w).Encode(respond(req)))
+			return
+		}
+		if !batchSupported {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		*batches++
+		var reqs []testRPCRequest
+		require.NoError(t, json.Unmarshal(body, &reqs))
+		resps := make([]map[string]any, len(reqs))
+		for i, req := range reqs {
+			resps[i] = respond(req)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resps))
+	}))
+}
+
+func newTestBatchMonitor(t *testing.T, url string) *Monitor {
+	l2Client, err := ethclient.Dial(url)
+	require.NoError(t, err)
+	t.Cleanup(l2Client.Close)
+
+	m := newTestRetryMonitor(t, 0)
+	m.l2Client = l2Client
+	m.nodeConnectionFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nodeConnectionFailures",
+	}, []string{"layer", "section"})
+	m.batchRPC.Store(true)
+	return m
+}
+
+func TestL2HeightAndBlock(t *testing.T) {
+	header := &types.Header{
+		Number:     big.NewInt(100),
+		Difficulty: big.NewInt(0),
+		Root:       [32]byte{1},
+		TxHash:     types.EmptyTxsHash,
+		UncleHash:  types.EmptyUncleHash,
+	}
+
+	t.Run("Batched", func(t *testing.T) {
+		var batches int
+		srv := newTestL2Server(t, header, true, &batches)
+		defer srv.Close()
+
+		m := newTestBatchMonitor(t, srv.URL)
+		height, block, err := m.l2HeightAndBlock(context.Background(), big.NewInt(100))
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), height)
+		require.Equal(t, header.Hash(), block.Hash())
+		require.Equal(t, 1, batches)
+		require.True(t, m.batchRPC.Load())
+	})
+
+	t.Run("NotSynced", func(t *testing.T) {
+		var batches int
+		srv := newTestL2Server(t, header, true, &batches)
+		defer srv.Close()
+
+		m := newTestBatchMonitor(t, srv.URL)
+		height, block, err := m.l2HeightAndBlock(context.Background(), big.NewInt(101))
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), height)
+		require.Nil(t, block)
+	})
+
+	t.Run("FallsBackWhenUnsupported", func(t *testing.T) {
+		var batches int
+		srv := newTestL2Server(t, header, false, &batches)
+		defer srv.Close()
+
+		m := newTestBatchMonitor(t, srv.URL)
+		height, block, err := m.l2HeightAndBlock(context.Background(), big.NewInt(100))
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), height)
+		require.Equal(t, header.Hash(), block.Hash())
+		require.False(t, m.batchRPC.Load())
+	})
+
+	t.Run("ByHash", func(t *testing.T) {
+		var batches int
+		srv := newTestL2Server(t, header, false, &batches)
+		defer srv.Close()
+
+		m := newTestBatchMonitor(t, srv.URL)
+		m.batchRPC.Store(false)
+		m.fetchBlockByHash = true
+		height, block, err := m.l2HeightAndBlock(context.Background(), big.NewInt(100))
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), height)
+		require.Equal(t, header.Hash(), block.Hash())
+		require.Equal(t, float64(1), testutil.ToFloat64(m.rpcCallsTotal.WithLabelValues("l2", "blockByHash")))
+	})
+}
+
+func TestProbeL2MethodsWithoutGetProof(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), Difficulty: big.NewInt(0)}
+	var batches int
+	srv := newTestL2Server(t, header, false, &batches)
+	defer srv.Close()
+
+	client, err := ethclient.Dial(srv.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = probeL2Methods(context.Background(), client, "l2", common.Address{}, false)
+	require.ErrorContains(t, err, "l2 node does not support eth_getProof")
+}
+
+func TestConfirmedL1Block(t *testing.T) {
+	srv := newTestL2Server(t, &types.Header{Number: big.NewInt(100), Difficulty: common.Big0}, false, new(int))
+	defer srv.Close()
+	client, err := ethclient.Dial(srv.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	m := newTestRetryMonitor(t, 0)
+	m.l1Client = &failoverClient{clients: []*ethclient.Client{client}}
+
+	block, err := m.confirmedL1Block(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, block, "read at the head without confirmations")
+
+	m.l1ConfirmationBlocks = 10
+	block, err = m.confirmedL1Block(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(90), block.Uint64())
+
+	m.l1ConfirmationBlocks = 200
+	block, err = m.confirmedL1Block(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, block.Uint64())
+}
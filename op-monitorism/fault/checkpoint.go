@@ -0,0 +1,105 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// verifyCheckpoint re-validates the last output validated before the checkpoint was written,
+// as a stale checkpoint may no longer hold after an l1 reorg or when pointed at another chain.
+// Returns false if the checkpoint is past the posted outputs or the output now mismatches, in
+// which case the checkpoint is discarded. Failing to check the output keeps the checkpoint.
+func (m *Monitor) verifyCheckpoint(ctx context.Context, checkpoint uint64) bool {
+	nextOutputIndex, err := withRetries(ctx, m, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx})
+	})
+	if err != nil {
+		m.log.Warn("failed to verify checkpoint, resuming from it", "index", checkpoint, "err", err)
+		return ctx.Err() == nil
+	}
+	if checkpoint > nextOutputIndex.Uint64() {
+		m.log.Warn("checkpoint is past the next output index, discarding it", "index", checkpoint, "next_index", nextOutputIndex)
+		return false
+	}
+	if checkpoint == 0 {
+		return true
+	}
+
+	check, err := m.checkOutput(ctx, checkpoint-1)
+	if err != nil {
+		m.log.Warn("failed to verify checkpoint, resuming from it", "index", checkpoint, "err", err)
+		return ctx.Err() == nil
+	}
+	if check.mismatched() {
+		m.log.Error("output validated before the checkpoint now mismatches, discarding the checkpoint", "index", checkpoint, "output_index", check.index)
+		return false
+	}
+	m.log.Info("verified checkpoint", "index", checkpoint)
+	return true
+}
+
+// readCheckpoint returns the output index persisted at the given path, and the indexes of the
+// outputs behind it still mismatched, listed on the line following the index if any
+func readCheckpoint(path string) (uint64, []uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	lines := strings.SplitN(strings.TrimSpace(string(data)), "\n", 2)
+	index, err := strconv.ParseUint(strings.TrimSpace(lines[0]), 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("malformed checkpoint: %w", err)
+	}
+	var mismatched []uint64
+	if len(lines) > 1 {
+		for _, field := range strings.Fields(lines[1]) {
+			mismatch, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, nil, fmt.Errorf("malformed checkpoint mismatch: %w", err)
+			}
+			mismatched = append(mismatched, mismatch)
+		}
+	}
+	return index, mismatched, nil
+}
+
+// writeCheckpoint persists the output index at the given path, followed by the indexes of the
+// outputs behind it still mismatched, so they are not forgotten across restarts. The checkpoint
+// is written to a temporary file first and renamed over the checkpoint so that a crash never
+// leaves a partially written checkpoint behind.
+func writeCheckpoint(path string, index uint64, mismatched []uint64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	contents := strconv.FormatUint(index, 10) + "\n"
+	if len(mismatched) > 0 {
+		fields := make([]string, len(mismatched))
+		for i, mismatch := range mismatched {
+			fields[i] = strconv.FormatUint(mismatch, 10)
+		}
+		contents += strings.Join(fields, " ") + "\n"
+	}
+	if _, err := tmp.WriteString(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
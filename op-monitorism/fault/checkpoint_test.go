@@ -0,0 +1,33 @@
+package fault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	_, _, err := readCheckpoint(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, writeCheckpoint(path, 42, nil))
+	index, mismatched, err := readCheckpoint(path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), index)
+	require.Empty(t, mismatched)
+
+	// with the mismatches skipped past
+	require.NoError(t, writeCheckpoint(path, 43, []uint64{7, 40}))
+	index, mismatched, err = readCheckpoint(path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(43), index)
+	require.Equal(t, []uint64{7, 40}, mismatched)
+
+	require.NoError(t, os.WriteFile(path, []byte("not an index"), 0o644))
+	_, _, err = readCheckpoint(path)
+	require.ErrorContains(t, err, "malformed checkpoint")
+}
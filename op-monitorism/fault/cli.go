@@ -1,36 +1,560 @@
 package fault
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	monitorism "github.com/ethereum-optimism/monitorism/op-monitorism"
 
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
 
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
 const (
-	L1NodeURLFlagName = "l1.node.url"
-	L2NodeURLFlagName = "l2.node.url"
+	L1NodeURLFlagName     = "l1.node.url"
+	L2NodeURLFlagName     = "l2.node.url"
+	RollupNodeURLFlagName = "rollup.node.url"
+	L1ChainIDFlagName     = "l1.chain.id"
+	L2ChainIDFlagName     = "l2.chain.id"
+	L1RPCHeadersFlagName  = "l1.rpc.headers"
+	L2RPCHeadersFlagName  = "l2.rpc.headers"
+
+	L1TLSCertFlagName = "l1.tls.cert"
+	L1TLSKeyFlagName  = "l1.tls.key"
+	L1TLSCAFlagName   = "l1.tls.ca"
+	L2TLSCertFlagName = "l2.tls.cert"
+	L2TLSKeyFlagName  = "l2.tls.key"
+	L2TLSCAFlagName   = "l2.tls.ca"
+
+	RPCProxyURLFlagName = "rpc.proxy.url"
+
+	L2NodeURLSecondaryFlagName = "l2.node.url.secondary"
+	L1WSURLFlagName            = "l1.ws.url"
 
 	OptimismPortalAddressFlagName = "optimismportal.address"
+	L2OutputOracleAddressFlagName = "l2outputoracle.address"
+	MessagePasserAddressFlagName  = "message.passer.address"
 	StartOutputIndexFlagName      = "start.output.index"
+	StartFromLatestFlagName       = "start.from.latest"
+	ContinueOnMismatchFlagName    = "continue.on.mismatch"
+	MismatchConfirmationsFlagName = "mismatch.confirmations"
+	L1ConfirmationBlocksFlagName  = "l1.confirmation.blocks"
+
+	IgnoredMismatchIndexesFlagName   = "ignored.mismatch.indexes"
+	MaxConsecutiveMismatchesFlagName = "max.consecutive.mismatches"
+
+	RPCRetriesFlagName       = "rpc.retries"
+	RPCRetryBaseMsecFlagName = "rpc.retry.base.msec"
+	RPCTimeoutMsecFlagName   = "rpc.timeout.msec"
+
+	ConfigFlagName       = "config"
+	ChainsConfigFlagName = "chains.config"
+
+	CheckpointPathFlagName = "checkpoint.path"
+	AuditLogPathFlagName   = "audit.log.path"
+
+	WebhookURLFlagName      = "webhook.url"
+	SlackWebhookURLFlagName = "slack.webhook.url"
+
+	WebhookBatchMsecFlagName = "webhook.batch.msec"
+	WebhookBatchSizeFlagName = "webhook.batch.size"
+	WebhookGzipFlagName      = "webhook.gzip"
+
+	WebhookSigningSecretFlagName = "webhook.signing.secret"
+
+	AlertDedupSecondsFlagName = "alert.dedup.seconds"
+
+	StartupGraceSecondsFlagName = "startup.grace.seconds"
+
+	MaxSyncWaitTicksFlagName = "max.sync.wait.ticks"
+	L2BehindBehaviorFlagName = "l2.behind.behavior"
+
+	MaxOutputGapSecondsFlagName = "max.output.gap.seconds"
+	MaxStuckTicksFlagName       = "max.stuck.ticks"
+	WaitLogIntervalSecFlagName  = "wait.log.interval.seconds"
+	MaxL2TimeSkewSecFlagName    = "max.l2.time.skew.seconds"
+
+	WindowRefreshTicksFlagName = "window.refresh.ticks"
+
+	FinalizationWindowOverrideSecondsFlagName = "finalization.window.override.seconds"
+
+	DebugReconstructionFlagName = "debug.reconstruction"
+
+	ProofBlockTagFlagName = "proof.block.tag"
+	OutputVersionFlagName = "output.version"
+	L2BatchRPCFlagName    = "l2.batch.rpc"
+
+	FetchBlockByHashFlagName = "l2.fetch.block.by.hash"
+
+	CheckCanonicalBlockFlagName = "check.canonical.block"
+	VerifyProofLocallyFlagName  = "verify.proof.locally"
+
+	InjectFaultAtIndexFlagName = "inject.fault.at.index"
+
+	MetricsNamespaceFlagName = "metrics.namespace"
+	MetricsSubsystemFlagName = "metrics.subsystem"
+	MetricsLabelsFlagName    = "metrics.labels"
+
+	HealthEnabledFlagName = "health.enabled"
+	HealthAddrFlagName    = "health.addr"
+	HealthPortFlagName    = "health.port"
+
+	AdminEnabledFlagName = "admin.enabled"
+
+	MaxOutputsPerTickFlagName = "max.outputs.per.tick"
+
+	CatchUpThresholdFlagName = "catchup.threshold"
+	MaxConcurrencyFlagName   = "max.concurrency"
+
+	CatchUpProgressIntervalFlagName = "catchup.progress.interval"
+
+	SampleEveryNFlagName = "sample.every.n"
+
+	EndOutputIndexFlagName = "end.output.index"
+	MinOutputIndexFlagName = "min.output.index"
+	BlockNumberFlagName    = "block.number"
+	ReportPathFlagName     = "report.path"
+
+	OtherL1NodeURLFlagName             = "other.l1.node.url"
+	OtherL2OutputOracleAddressFlagName = "other.l2outputoracle.address"
+)
+
+// Behaviors once the l2 node is stuck behind the output being checked
+const (
+	L2BehindBehaviorWait  = "wait"
+	L2BehindBehaviorSkip  = "skip"
+	L2BehindBehaviorError = "error"
+)
+
+// Block tags the message passer storage proof can be requested at
+const (
+	ProofBlockTagHash   = "hash"
+	ProofBlockTagNumber = "number"
 )
 
+// metricsLabelName matches the valid names of prometheus labels
+var metricsLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 type CLIConfig struct {
 	L1NodeURL string
 	L2NodeURL string
 
+	// optional, cross-checks reconstructed output roots
+	RollupNodeURL string
+
+	// optional, independent l2 node required to agree with the l2 node
+	L2NodeURLSecondary string
+
+	// optional, websocket l1 endpoint subscribed to proposed outputs
+	L1WSURL string
+
+	// headers sent with every request, such as authorization
+	L1RPCHeaders http.Header
+	L2RPCHeaders http.Header
+
+	// optional client certificate and CA of mutual-TLS endpoints
+	L1TLSCert string
+	L1TLSKey  string
+	L1TLSCA   string
+	L2TLSCert string
+	L2TLSKey  string
+	L2TLSCA   string
+
+	// optional http or socks5 proxy of every rpc client, in place of the HTTP_PROXY and
+	// HTTPS_PROXY environment variables
+	RPCProxyURL string
+
+	// expected chain ids of the nodes, unchecked if zero
+	L1ChainID uint64
+	L2ChainID uint64
+
 	OptimismPortalAddress common.Address
 	StartOutputIndex      int64
+
+	// binds the oracle directly if set, rather than resolving it from the portal
+	L2OutputOracleAddress common.Address
+
+	// skips posted outputs, only validating outputs proposed from startup
+	StartFromLatest bool
+
+	// overrides the L2ToL1MessagePasser predeploy if set
+	MessagePasserAddress common.Address
+
+	ContinueOnMismatch    bool
+	MismatchConfirmations uint64
+
+	// outputs are only considered once proposed this many blocks behind the l1 head
+	L1ConfirmationBlocks uint64
+
+	// known and accepted mismatched indexes, advanced past without alerting
+	IgnoredMismatchIndexes []uint64
+
+	// pauses validation after this many distinct indexes mismatch in a row, disabled if zero
+	MaxConsecutiveMismatches uint64
+
+	RPCRetries     uint64
+	RPCRetryBaseMs uint64
+	RPCTimeoutMs   uint64
+
+	CheckpointPath string
+
+	// optional, file every checked output is appended to as a JSONL record
+	AuditLogPath string
+
+	WebhookURL      string
+	SlackWebhookURL string
+
+	// mismatch events are posted to the webhook in batches every interval if set, bounded
+	// to the batch size if also set. Posted immediately otherwise
+	WebhookBatchMs   uint64
+	WebhookBatchSize uint64
+	WebhookGzip      bool
+
+	// signs the JSON body of posts to the webhook with an HMAC-SHA256 if set
+	WebhookSigningSecret string
+
+	// repeated notifications of the same mismatch are suppressed until the window elapses,
+	// or until the mismatch clears if zero
+	AlertDedupSeconds uint64
+
+	// notifications of mismatches are held back for this long after startup, and only sent
+	// if the mismatch is confirmed once the grace period passed. Disabled if zero
+	StartupGraceSeconds uint64
+
+	MaxSyncWaitTicks uint64
+
+	// behavior once the l2 node is behind the output past the max sync wait ticks, either
+	// waiting, skipping the output or failing the loop
+	L2BehindBehavior string
+
+	// time without new outputs before the proposer is considered stalled, disabled if zero
+	MaxOutputGapSeconds uint64
+
+	// loops with outputs available without advancing before the monitor is considered stuck
+	MaxStuckTicks uint64
+
+	// minimum interval between logs of waiting for the next output, logged every loop if zero
+	WaitLogIntervalSec uint64
+
+	// skew between the local clock and the latest l2 block time warned about, disabled if zero
+	MaxL2TimeSkewSec uint64
+
+	// defaults to one output per loop if zero
+	MaxOutputsPerTick uint64
+
+	CatchUpThreshold uint64
+	// defaults to 8 outputs checked concurrently if zero
+	MaxConcurrency uint64
+
+	// catch-up progress is logged every interval of indexes, disabled if zero
+	CatchUpProgressInterval uint64
+
+	// only outputs at multiples of this index are validated, every output if one
+	SampleEveryN uint64
+
+	WindowRefreshTicks uint64
+
+	// replaces the on-chain finalization period if set
+	FinalizationWindowOverrideSeconds uint64
+
+	DebugReconstruction bool
+
+	// block tag of the storage proof, either by number or hash. By number if unset
+	ProofBlockTag string
+
+	// cross-checks the l2 block is canonical before reconstructing from it
+	CheckCanonicalBlock bool
+
+	// verifies the account proof of the message passer against the state root of the l2
+	// block, rather than trusting the storage root of the response
+	VerifyProofLocally bool
+
+	// batches l2 reads into fewer round trips
+	L2BatchRPC bool
+
+	// fetches the l2 block of an output by the hash of the header at its number, for
+	// providers slow to serve full blocks by number
+	FetchBlockByHash bool
+
+	// output index reported as mismatched to test alerting, disabled if negative
+	InjectFaultAtIndex int64
+
+	// prefix of the metric names, defaulting to MetricsNamespace if empty
+	MetricsNamespace string
+	MetricsSubsystem string
+
+	// constant labels attached to every metric, such as the network name
+	MetricsLabels map[string]string
+
+	HealthEnabled bool
+	HealthAddr    string
+	HealthPort    int
+
+	// serves the admin endpoints on the health server
+	AdminEnabled bool
+
+	// loop interval of the monitor, from the shared flags
+	LoopIntervalMs uint64
+
+	// version of the output roots posted by the chain. Only version 0 is verified
+	// without an OutputVerifier
+	OutputVersion uint64
+
+	// verifies outputs against l2, defaulting to OutputV0Verifier if nil. Not
+	// configurable from flags, for embedders supporting other output root versions
+	OutputVerifier OutputVerifier
+
+	// time source of the time-dependent checks, defaulting to the system clock if nil.
+	// Not configurable from flags, for tests advancing time manually
+	Clock clock.Clock
+
+	// When set, a monitor is run for each chain, which
+	// take precedence over the single chain configuration
+	Chains []ChainConfig
+}
+
+// ChainConfig configures a single chain of a multi-chain monitor
+type ChainConfig struct {
+	Name                  string         `yaml:"name"`
+	L1NodeURL             string         `yaml:"l1_node_url"`
+	L2NodeURL             string         `yaml:"l2_node_url"`
+	RollupNodeURL         string         `yaml:"rollup_node_url"`
+	L2NodeURLSecondary    string         `yaml:"l2_node_url_secondary"`
+	L1WSURL               string         `yaml:"l1_ws_url"`
+	L1ChainID             uint64         `yaml:"l1_chain_id"`
+	L2ChainID             uint64         `yaml:"l2_chain_id"`
+	OptimismPortalAddress common.Address `yaml:"optimism_portal_address"`
+	L2OutputOracleAddress common.Address `yaml:"l2_output_oracle_address"`
+	MessagePasserAddress  common.Address `yaml:"message_passer_address"`
+
+	L1RPCHeaders map[string]string `yaml:"l1_rpc_headers"`
+	L2RPCHeaders map[string]string `yaml:"l2_rpc_headers"`
+}
+
+// ChainsConfig is the structure of the file listing the chains to monitor
+type ChainsConfig struct {
+	Chains []ChainConfig `yaml:"chains"`
+}
+
+// ForChain returns a copy of the config targeting the given chain. Chain
+// agnostic options are shared by all chains, other than the health server
+// run once for all chains.
+func (c CLIConfig) ForChain(chain ChainConfig) CLIConfig {
+	c.L1NodeURL = chain.L1NodeURL
+	c.L2NodeURL = chain.L2NodeURL
+	c.RollupNodeURL = chain.RollupNodeURL
+	c.L2NodeURLSecondary = chain.L2NodeURLSecondary
+	c.L1WSURL = chain.L1WSURL
+	c.L1ChainID = chain.L1ChainID
+	c.L2ChainID = chain.L2ChainID
+	c.OptimismPortalAddress = chain.OptimismPortalAddress
+	c.L2OutputOracleAddress = chain.L2OutputOracleAddress
+	if chain.MessagePasserAddress != (common.Address{}) {
+		c.MessagePasserAddress = chain.MessagePasserAddress
+	}
+	if len(chain.L1RPCHeaders) > 0 {
+		c.L1RPCHeaders = headersFromMap(chain.L1RPCHeaders)
+	}
+	if len(chain.L2RPCHeaders) > 0 {
+		c.L2RPCHeaders = headersFromMap(chain.L2RPCHeaders)
+	}
+	if c.CheckpointPath != "" {
+		c.CheckpointPath = c.CheckpointPath + "." + chain.Name
+	}
+	if c.AuditLogPath != "" {
+		c.AuditLogPath = c.AuditLogPath + "." + chain.Name
+	}
+	c.Chains = nil
+	c.HealthEnabled = false
+	return c
 }
 
 func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
+	if err := applyConfigFile(ctx); err != nil {
+		return CLIConfig{}, err
+	}
+
 	cfg := CLIConfig{
 		L1NodeURL:        ctx.String(L1NodeURLFlagName),
 		L2NodeURL:        ctx.String(L2NodeURLFlagName),
+		RollupNodeURL:    ctx.String(RollupNodeURLFlagName),
+		L1ChainID:        ctx.Uint64(L1ChainIDFlagName),
+		L2ChainID:        ctx.Uint64(L2ChainIDFlagName),
 		StartOutputIndex: ctx.Int64(StartOutputIndexFlagName),
+		StartFromLatest:  ctx.Bool(StartFromLatestFlagName),
+
+		L2NodeURLSecondary: ctx.String(L2NodeURLSecondaryFlagName),
+		L1WSURL:            ctx.String(L1WSURLFlagName),
+
+		L1TLSCert: ctx.String(L1TLSCertFlagName),
+		L1TLSKey:  ctx.String(L1TLSKeyFlagName),
+		L1TLSCA:   ctx.String(L1TLSCAFlagName),
+		L2TLSCert: ctx.String(L2TLSCertFlagName),
+		L2TLSKey:  ctx.String(L2TLSKeyFlagName),
+		L2TLSCA:   ctx.String(L2TLSCAFlagName),
+
+		RPCProxyURL: ctx.String(RPCProxyURLFlagName),
+
+		ContinueOnMismatch:    ctx.Bool(ContinueOnMismatchFlagName),
+		MismatchConfirmations: ctx.Uint64(MismatchConfirmationsFlagName),
+		L1ConfirmationBlocks:  ctx.Uint64(L1ConfirmationBlocksFlagName),
+
+		IgnoredMismatchIndexes:   ctx.Uint64Slice(IgnoredMismatchIndexesFlagName),
+		MaxConsecutiveMismatches: ctx.Uint64(MaxConsecutiveMismatchesFlagName),
+
+		RPCRetries:     ctx.Uint64(RPCRetriesFlagName),
+		RPCRetryBaseMs: ctx.Uint64(RPCRetryBaseMsecFlagName),
+		RPCTimeoutMs:   ctx.Uint64(RPCTimeoutMsecFlagName),
+
+		CheckpointPath: ctx.String(CheckpointPathFlagName),
+		AuditLogPath:   ctx.String(AuditLogPathFlagName),
+
+		WebhookURL:      ctx.String(WebhookURLFlagName),
+		SlackWebhookURL: ctx.String(SlackWebhookURLFlagName),
+
+		WebhookBatchMs:   ctx.Uint64(WebhookBatchMsecFlagName),
+		WebhookBatchSize: ctx.Uint64(WebhookBatchSizeFlagName),
+		WebhookGzip:      ctx.Bool(WebhookGzipFlagName),
+
+		WebhookSigningSecret: ctx.String(WebhookSigningSecretFlagName),
+
+		AlertDedupSeconds: ctx.Uint64(AlertDedupSecondsFlagName),
+
+		StartupGraceSeconds: ctx.Uint64(StartupGraceSecondsFlagName),
+
+		MaxSyncWaitTicks: ctx.Uint64(MaxSyncWaitTicksFlagName),
+		L2BehindBehavior: ctx.String(L2BehindBehaviorFlagName),
+
+		MaxOutputGapSeconds: ctx.Uint64(MaxOutputGapSecondsFlagName),
+		MaxStuckTicks:       ctx.Uint64(MaxStuckTicksFlagName),
+		WaitLogIntervalSec:  ctx.Uint64(WaitLogIntervalSecFlagName),
+		MaxL2TimeSkewSec:    ctx.Uint64(MaxL2TimeSkewSecFlagName),
+
+		MaxOutputsPerTick: ctx.Uint64(MaxOutputsPerTickFlagName),
+
+		CatchUpThreshold: ctx.Uint64(CatchUpThresholdFlagName),
+		MaxConcurrency:   ctx.Uint64(MaxConcurrencyFlagName),
+
+		CatchUpProgressInterval: ctx.Uint64(CatchUpProgressIntervalFlagName),
+
+		SampleEveryN: ctx.Uint64(SampleEveryNFlagName),
+
+		WindowRefreshTicks: ctx.Uint64(WindowRefreshTicksFlagName),
+
+		FinalizationWindowOverrideSeconds: ctx.Uint64(FinalizationWindowOverrideSecondsFlagName),
+
+		DebugReconstruction: ctx.Bool(DebugReconstructionFlagName),
+
+		ProofBlockTag: ctx.String(ProofBlockTagFlagName),
+		OutputVersion: ctx.Uint64(OutputVersionFlagName),
+		L2BatchRPC:    ctx.Bool(L2BatchRPCFlagName),
+
+		FetchBlockByHash: ctx.Bool(FetchBlockByHashFlagName),
+
+		CheckCanonicalBlock: ctx.Bool(CheckCanonicalBlockFlagName),
+		VerifyProofLocally:  ctx.Bool(VerifyProofLocallyFlagName),
+
+		InjectFaultAtIndex: ctx.Int64(InjectFaultAtIndexFlagName),
+
+		MetricsNamespace: ctx.String(MetricsNamespaceFlagName),
+		MetricsSubsystem: ctx.String(MetricsSubsystemFlagName),
+
+		HealthEnabled: ctx.Bool(HealthEnabledFlagName),
+		HealthAddr:    ctx.String(HealthAddrFlagName),
+		HealthPort:    ctx.Int(HealthPortFlagName),
+
+		AdminEnabled: ctx.Bool(AdminEnabledFlagName),
+
+		LoopIntervalMs: ctx.Uint64(monitorism.LoopIntervalMsecFlagName),
+	}
+
+	switch cfg.L2BehindBehavior {
+	case L2BehindBehaviorWait, L2BehindBehaviorSkip, L2BehindBehaviorError:
+	default:
+		return cfg, fmt.Errorf("--%s must be one of %q, %q or %q", L2BehindBehaviorFlagName, L2BehindBehaviorWait, L2BehindBehaviorSkip, L2BehindBehaviorError)
+	}
+	if cfg.MaxOutputsPerTick == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", MaxOutputsPerTickFlagName)
+	}
+	if cfg.StartFromLatest && cfg.StartOutputIndex >= 0 {
+		return cfg, fmt.Errorf("--%s cannot be combined with --%s", StartFromLatestFlagName, StartOutputIndexFlagName)
+	}
+	if cfg.ProofBlockTag != ProofBlockTagHash && cfg.ProofBlockTag != ProofBlockTagNumber {
+		return cfg, fmt.Errorf("--%s must be either %q or %q", ProofBlockTagFlagName, ProofBlockTagHash, ProofBlockTagNumber)
+	}
+	if cfg.SampleEveryN == 0 {
+		return cfg, fmt.Errorf("--%s must be positive", SampleEveryNFlagName)
+	}
+	if cfg.SampleEveryN > 1 && cfg.CatchUpThreshold > 0 {
+		return cfg, fmt.Errorf("--%s cannot be combined with --%s", SampleEveryNFlagName, CatchUpThresholdFlagName)
+	}
+	if cfg.CatchUpThreshold > 0 && cfg.MaxConcurrency == 0 {
+		return cfg, fmt.Errorf("--%s must be positive when --%s is set", MaxConcurrencyFlagName, CatchUpThresholdFlagName)
+	}
+	if cfg.L2BatchRPC && cfg.FetchBlockByHash {
+		return cfg, fmt.Errorf("--%s cannot be combined with --%s", FetchBlockByHashFlagName, L2BatchRPCFlagName)
+	}
+	if cfg.AdminEnabled && !cfg.HealthEnabled {
+		return cfg, fmt.Errorf("--%s requires --%s", AdminEnabledFlagName, HealthEnabledFlagName)
+	}
+	if cfg.WebhookBatchSize > 0 && cfg.WebhookBatchMs == 0 {
+		return cfg, fmt.Errorf("--%s requires --%s", WebhookBatchSizeFlagName, WebhookBatchMsecFlagName)
+	}
+	if (cfg.L1TLSCert == "") != (cfg.L1TLSKey == "") {
+		return cfg, fmt.Errorf("--%s and --%s must be set together", L1TLSCertFlagName, L1TLSKeyFlagName)
+	}
+	if (cfg.L2TLSCert == "") != (cfg.L2TLSKey == "") {
+		return cfg, fmt.Errorf("--%s and --%s must be set together", L2TLSCertFlagName, L2TLSKeyFlagName)
+	}
+	if _, err := parseProxyURL(cfg.RPCProxyURL); err != nil {
+		return cfg, fmt.Errorf("--%s: %w", RPCProxyURLFlagName, err)
+	}
+
+	var err error
+	if cfg.L1RPCHeaders, err = parseHeaders(L1RPCHeadersFlagName, ctx.StringSlice(L1RPCHeadersFlagName)); err != nil {
+		return cfg, err
+	}
+	if cfg.L2RPCHeaders, err = parseHeaders(L2RPCHeadersFlagName, ctx.StringSlice(L2RPCHeadersFlagName)); err != nil {
+		return cfg, err
+	}
+	if cfg.MetricsLabels, err = parseMetricsLabels(ctx.StringSlice(MetricsLabelsFlagName)); err != nil {
+		return cfg, err
+	}
+
+	if messagePasserAddress := ctx.String(MessagePasserAddressFlagName); messagePasserAddress != "" {
+		if !common.IsHexAddress(messagePasserAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", MessagePasserAddressFlagName)
+		}
+		cfg.MessagePasserAddress = common.HexToAddress(messagePasserAddress)
+	}
+
+	if chainsConfigPath := ctx.String(ChainsConfigFlagName); chainsConfigPath != "" {
+		chains, err := readChainsConfig(chainsConfigPath)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read --%s: %w", ChainsConfigFlagName, err)
+		}
+		if _, ok := cfg.MetricsLabels["chain"]; ok {
+			return cfg, fmt.Errorf("--%s must not set the chain label, set by --%s", MetricsLabelsFlagName, ChainsConfigFlagName)
+		}
+		cfg.Chains = chains
+		return cfg, nil
+	}
+
+	if oracleAddress := ctx.String(L2OutputOracleAddressFlagName); oracleAddress != "" {
+		if !common.IsHexAddress(oracleAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", L2OutputOracleAddressFlagName)
+		}
+		cfg.L2OutputOracleAddress = common.HexToAddress(oracleAddress)
+		return cfg, nil
 	}
 
 	portalAddress := ctx.String(OptimismPortalAddressFlagName)
@@ -42,11 +566,179 @@ func ReadCLIFlags(ctx *cli.Context) (CLIConfig, error) {
 	return cfg, nil
 }
 
+// OutputRootCLIConfig is the configuration of the command reconstructing the output root of an l2 block
+type OutputRootCLIConfig struct {
+	L2NodeURL            string
+	L2RPCHeaders         http.Header
+	MessagePasserAddress common.Address
+	BlockNumber          uint64
+
+	L2TLSCert string
+	L2TLSKey  string
+	L2TLSCA   string
+
+	RPCProxyURL string
+}
+
+func ReadOutputRootCLIFlags(ctx *cli.Context) (OutputRootCLIConfig, error) {
+	cfg := OutputRootCLIConfig{
+		L2NodeURL:   ctx.String(L2NodeURLFlagName),
+		BlockNumber: ctx.Uint64(BlockNumberFlagName),
+
+		L2TLSCert: ctx.String(L2TLSCertFlagName),
+		L2TLSKey:  ctx.String(L2TLSKeyFlagName),
+		L2TLSCA:   ctx.String(L2TLSCAFlagName),
+
+		RPCProxyURL: ctx.String(RPCProxyURLFlagName),
+	}
+	if _, err := parseProxyURL(cfg.RPCProxyURL); err != nil {
+		return cfg, fmt.Errorf("--%s: %w", RPCProxyURLFlagName, err)
+	}
+
+	var err error
+	if cfg.L2RPCHeaders, err = parseHeaders(L2RPCHeadersFlagName, ctx.StringSlice(L2RPCHeadersFlagName)); err != nil {
+		return cfg, err
+	}
+	if messagePasserAddress := ctx.String(MessagePasserAddressFlagName); messagePasserAddress != "" {
+		if !common.IsHexAddress(messagePasserAddress) {
+			return cfg, fmt.Errorf("--%s is not a hex-encoded address", MessagePasserAddressFlagName)
+		}
+		cfg.MessagePasserAddress = common.HexToAddress(messagePasserAddress)
+	}
+	return cfg, nil
+}
+
+// CompareOraclesCLIConfig is the configuration of the command comparing the outputs of two oracles
+type CompareOraclesCLIConfig struct {
+	L1NodeURL    string
+	L1RPCHeaders http.Header
+
+	OracleAddress common.Address
+
+	// l1 node of the other oracle, defaulting to the same l1 node if empty
+	OtherL1NodeURL     string
+	OtherOracleAddress common.Address
+
+	StartIndex uint64
+	EndIndex   uint64
+
+	L1TLSCert string
+	L1TLSKey  string
+	L1TLSCA   string
+
+	RPCProxyURL string
+}
+
+func ReadCompareOraclesCLIFlags(ctx *cli.Context) (CompareOraclesCLIConfig, error) {
+	cfg := CompareOraclesCLIConfig{
+		L1NodeURL:      ctx.String(L1NodeURLFlagName),
+		OtherL1NodeURL: ctx.String(OtherL1NodeURLFlagName),
+		EndIndex:       ctx.Uint64(EndOutputIndexFlagName),
+
+		L1TLSCert: ctx.String(L1TLSCertFlagName),
+		L1TLSKey:  ctx.String(L1TLSKeyFlagName),
+		L1TLSCA:   ctx.String(L1TLSCAFlagName),
+
+		RPCProxyURL: ctx.String(RPCProxyURLFlagName),
+	}
+	if _, err := parseProxyURL(cfg.RPCProxyURL); err != nil {
+		return cfg, fmt.Errorf("--%s: %w", RPCProxyURLFlagName, err)
+	}
+
+	startIndex := ctx.Int64(StartOutputIndexFlagName)
+	if startIndex < 0 {
+		return cfg, fmt.Errorf("--%s must be set", StartOutputIndexFlagName)
+	}
+	cfg.StartIndex = uint64(startIndex)
+
+	var err error
+	if cfg.L1RPCHeaders, err = parseHeaders(L1RPCHeadersFlagName, ctx.StringSlice(L1RPCHeadersFlagName)); err != nil {
+		return cfg, err
+	}
+	oracleAddress := ctx.String(L2OutputOracleAddressFlagName)
+	if !common.IsHexAddress(oracleAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", L2OutputOracleAddressFlagName)
+	}
+	cfg.OracleAddress = common.HexToAddress(oracleAddress)
+
+	otherOracleAddress := ctx.String(OtherL2OutputOracleAddressFlagName)
+	if !common.IsHexAddress(otherOracleAddress) {
+		return cfg, fmt.Errorf("--%s is not a hex-encoded address", OtherL2OutputOracleAddressFlagName)
+	}
+	cfg.OtherOracleAddress = common.HexToAddress(otherOracleAddress)
+	return cfg, nil
+}
+
+// parseHeaders parses the flag's list of headers, each formatted as "Name=Value"
+func parseHeaders(flag string, values []string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, value := range values {
+		name, value, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("--%s must be formatted as Name=Value", flag)
+		}
+		headers.Add(strings.TrimSpace(name), value)
+	}
+	return headers, nil
+}
+
+// parseMetricsLabels parses the constant labels of the metrics, formatted as name=value
+func parseMetricsLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, value := range values {
+		name, value, ok := strings.Cut(value, "=")
+		if !ok || !metricsLabelName.MatchString(name) {
+			return nil, fmt.Errorf("--%s must be formatted as name=value, with names of letters, digits and underscores", MetricsLabelsFlagName)
+		}
+		labels[name] = value
+	}
+	return labels, nil
+}
+
+func headersFromMap(values map[string]string) http.Header {
+	headers := make(http.Header)
+	for name, value := range values {
+		headers.Set(name, value)
+	}
+	return headers
+}
+
+func readChainsConfig(path string) ([]ChainConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config ChainsConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if len(config.Chains) == 0 {
+		return nil, errors.New("no chains configured")
+	}
+
+	names := make(map[string]bool)
+	for _, chain := range config.Chains {
+		if chain.Name == "" {
+			return nil, errors.New("chain configured without a name")
+		}
+		if names[chain.Name] {
+			return nil, fmt.Errorf("chain %s configured more than once", chain.Name)
+		}
+		if chain.OptimismPortalAddress == (common.Address{}) && chain.L2OutputOracleAddress == (common.Address{}) {
+			return nil, fmt.Errorf("chain %s has no optimism_portal_address or l2_output_oracle_address", chain.Name)
+		}
+		names[chain.Name] = true
+	}
+
+	return config.Chains, nil
+}
+
 func CLIFlags(envVar string) []cli.Flag {
 	return []cli.Flag{
 		&cli.StringFlag{
 			Name:    L1NodeURLFlagName,
-			Usage:   "Node URL of L1 peer Geth node",
+			Usage:   "Node URL of L1 peer Geth node. A comma-separated list of URLs fails over to the next on repeated failures",
 			EnvVars: opservice.PrefixEnvVar(envVar, "L1_NODE_URL"),
 		},
 		&cli.StringFlag{
@@ -54,17 +746,441 @@ func CLIFlags(envVar string) []cli.Flag {
 			Usage:   "Node URL of L2 peer Op-Geth node",
 			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL"),
 		},
+		&cli.StringFlag{
+			Name:    RollupNodeURLFlagName,
+			Usage:   "Node URL of a trusted op-node, cross-checking reconstructed output roots with its optimism_outputAtBlock",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ROLLUP_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:    L2NodeURLSecondaryFlagName,
+			Usage:   "Node URL of an independent L2 Op-Geth node, which must agree with the L2 node on the reconstructed block and storage root",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_NODE_URL_SECONDARY"),
+		},
+		&cli.StringFlag{
+			Name:    L1WSURLFlagName,
+			Usage:   "Websocket URL of an L1 node, subscribed to the oracle's OutputProposed logs to validate outputs as soon as they are proposed",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_WS_URL"),
+		},
+		&cli.Uint64Flag{
+			Name:    L1ChainIDFlagName,
+			Usage:   "Expected chain id of the L1 node, checked on startup if set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_CHAIN_ID"),
+		},
+		&cli.Uint64Flag{
+			Name:    L2ChainIDFlagName,
+			Usage:   "Expected chain id of the L2 node, checked on startup if set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_CHAIN_ID"),
+		},
+		&cli.StringSliceFlag{
+			Name:    L1RPCHeadersFlagName,
+			Usage:   "Headers sent with every request to the L1 node, formatted as Name=Value, such as an Authorization bearer token",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_RPC_HEADERS"),
+		},
+		&cli.StringSliceFlag{
+			Name:    L2RPCHeadersFlagName,
+			Usage:   "Headers sent with every request to the L2 node, formatted as Name=Value, such as an Authorization bearer token",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_RPC_HEADERS"),
+		},
+		&cli.StringFlag{
+			Name:    L1TLSCertFlagName,
+			Usage:   "Path of the client certificate presented to mutual-TLS L1 endpoints, along with --l1.tls.key",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_TLS_CERT"),
+		},
+		&cli.StringFlag{
+			Name:    L1TLSKeyFlagName,
+			Usage:   "Path of the key of the L1 client certificate",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_TLS_KEY"),
+		},
+		&cli.StringFlag{
+			Name:    L1TLSCAFlagName,
+			Usage:   "Path of the CA certificate verifying L1 endpoints, in place of the system's certificate pool",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_TLS_CA"),
+		},
+		&cli.StringFlag{
+			Name:    L2TLSCertFlagName,
+			Usage:   "Path of the client certificate presented to mutual-TLS L2 endpoints, along with --l2.tls.key",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_TLS_CERT"),
+		},
+		&cli.StringFlag{
+			Name:    L2TLSKeyFlagName,
+			Usage:   "Path of the key of the L2 client certificate",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_TLS_KEY"),
+		},
+		&cli.StringFlag{
+			Name:    L2TLSCAFlagName,
+			Usage:   "Path of the CA certificate verifying L2 endpoints, in place of the system's certificate pool",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_TLS_CA"),
+		},
+		&cli.StringFlag{
+			Name:    RPCProxyURLFlagName,
+			Usage:   "URL of the http or socks5 proxy of every RPC client, such as socks5://127.0.0.1:1080. Defaults to the HTTP_PROXY and HTTPS_PROXY environment variables",
+			EnvVars: opservice.PrefixEnvVar(envVar, "RPC_PROXY_URL"),
+		},
 		&cli.Int64Flag{
 			Name:    StartOutputIndexFlagName,
 			Usage:   "Output index to start from. -1 to find first unfinalized index",
 			Value:   -1,
 			EnvVars: opservice.PrefixEnvVar(envVar, "START_OUTPUT_INDEX"),
 		},
+		&cli.BoolFlag{
+			Name:    StartFromLatestFlagName,
+			Usage:   "Start from the next output to be proposed, skipping the validation of all posted outputs",
+			EnvVars: opservice.PrefixEnvVar(envVar, "START_FROM_LATEST"),
+		},
+		&cli.StringFlag{
+			Name:    OptimismPortalAddressFlagName,
+			Usage:   "Address of the OptimismPortal contract. Required unless --" + ChainsConfigFlagName + " or --" + L2OutputOracleAddressFlagName + " is set",
+			EnvVars: opservice.PrefixEnvVar(envVar, "OPTIMISM_PORTAL"),
+		},
+		&cli.StringFlag{
+			Name:    L2OutputOracleAddressFlagName,
+			Usage:   "Address of the L2OutputOracle contract, bound directly instead of resolving it from the OptimismPortal",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_OUTPUT_ORACLE"),
+		},
+		&cli.StringFlag{
+			Name:    MessagePasserAddressFlagName,
+			Usage:   "Address of the L2ToL1MessagePasser contract, if not deployed at its predeploy address",
+			EnvVars: opservice.PrefixEnvVar(envVar, "MESSAGE_PASSER_ADDRESS"),
+		},
+		&cli.BoolFlag{
+			Name:    ContinueOnMismatchFlagName,
+			Usage:   "Continue validating subsequent outputs after a mismatch instead of halting on the faulty index",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CONTINUE_ON_MISMATCH"),
+		},
+		&cli.Uint64Flag{
+			Name:    MismatchConfirmationsFlagName,
+			Usage:   "Number of times a mismatched output is re-checked, confirming the mismatch before it is recorded",
+			EnvVars: opservice.PrefixEnvVar(envVar, "MISMATCH_CONFIRMATIONS"),
+		},
+		&cli.Uint64Flag{
+			Name:    L1ConfirmationBlocksFlagName,
+			Usage:   "Number of L1 blocks behind the head an output must be proposed at before it is checked, ignoring outputs that may still be reorged out. 0 to check outputs as soon as proposed",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L1_CONFIRMATION_BLOCKS"),
+		},
+		&cli.Uint64SliceFlag{
+			Name:    IgnoredMismatchIndexesFlagName,
+			Usage:   "Output indexes of known and accepted mismatches, logged and advanced past without flagging the monitor as mismatched",
+			EnvVars: opservice.PrefixEnvVar(envVar, "IGNORED_MISMATCH_INDEXES"),
+		},
+		&cli.Uint64Flag{
+			Name:    MaxConsecutiveMismatchesFlagName,
+			Usage:   "Pause validation once this many distinct output indexes mismatch in a row, until resumed through the health server with --admin.enabled or restarted. 0 to disable",
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAX_CONSECUTIVE_MISMATCHES"),
+		},
+		&cli.Uint64Flag{
+			Name:    RPCRetriesFlagName,
+			Usage:   "Number of times a failed RPC call is retried within a loop before giving up",
+			Value:   3,
+			EnvVars: opservice.PrefixEnvVar(envVar, "RPC_RETRIES"),
+		},
+		&cli.Uint64Flag{
+			Name:    RPCRetryBaseMsecFlagName,
+			Usage:   "Base backoff in milliseconds between RPC retries, doubled on each attempt",
+			Value:   250,
+			EnvVars: opservice.PrefixEnvVar(envVar, "RPC_RETRY_BASE_MSEC"),
+		},
+		&cli.Uint64Flag{
+			Name:    RPCTimeoutMsecFlagName,
+			Usage:   "Timeout in milliseconds of each RPC call attempt, so a hung connection fails the call rather than blocking the loop. 0 to disable",
+			Value:   10_000,
+			EnvVars: opservice.PrefixEnvVar(envVar, "RPC_TIMEOUT_MSEC"),
+		},
+		&cli.StringFlag{
+			Name:    ConfigFlagName,
+			Usage:   "Path to a yaml or toml file setting flags by name, overridden by the flags and environment",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CONFIG"),
+		},
+		&cli.StringFlag{
+			Name:    ChainsConfigFlagName,
+			Usage:   "Path to a yaml file listing multiple chains to monitor from this process",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHAINS_CONFIG"),
+		},
+		&cli.StringFlag{
+			Name:    CheckpointPathFlagName,
+			Usage:   "Path of a file persisting the next output index to check, used to resume on restart when the start index is -1",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHECKPOINT_PATH"),
+		},
+		&cli.StringFlag{
+			Name:    AuditLogPathFlagName,
+			Usage:   "Path of a file every checked output is appended to as a JSONL record, for an archive of validated outputs",
+			EnvVars: opservice.PrefixEnvVar(envVar, "AUDIT_LOG_PATH"),
+		},
+		&cli.StringFlag{
+			Name:    WebhookURLFlagName,
+			Usage:   "URL to which a JSON event is posted when an output root mismatch is detected",
+			EnvVars: opservice.PrefixEnvVar(envVar, "WEBHOOK_URL"),
+		},
+		&cli.StringFlag{
+			Name:    SlackWebhookURLFlagName,
+			Usage:   "Slack incoming webhook URL to which a message is posted when an output root mismatch is detected",
+			EnvVars: opservice.PrefixEnvVar(envVar, "SLACK_WEBHOOK_URL"),
+		},
+		&cli.Uint64Flag{
+			Name:    WebhookBatchMsecFlagName,
+			Usage:   "Interval in milliseconds at which mismatch events are posted to --webhook.url as a single batch. 0 to post each event immediately",
+			EnvVars: opservice.PrefixEnvVar(envVar, "WEBHOOK_BATCH_MSEC"),
+		},
+		&cli.Uint64Flag{
+			Name:    WebhookBatchSizeFlagName,
+			Usage:   "Maximum number of mismatch events in a batch, posted early once reached. 0 for unbounded batches",
+			EnvVars: opservice.PrefixEnvVar(envVar, "WEBHOOK_BATCH_SIZE"),
+		},
+		&cli.BoolFlag{
+			Name:    WebhookGzipFlagName,
+			Usage:   "Gzip-compress the body of posts to --webhook.url",
+			EnvVars: opservice.PrefixEnvVar(envVar, "WEBHOOK_GZIP"),
+		},
+		&cli.StringFlag{
+			Name:    WebhookSigningSecretFlagName,
+			Usage:   "Secret signing the JSON body of posts to --webhook.url with an HMAC-SHA256, sent hex encoded and prefixed with sha256= in the X-Monitorism-Signature header. The body is signed before any gzip compression",
+			EnvVars: opservice.PrefixEnvVar(envVar, "WEBHOOK_SIGNING_SECRET"),
+		},
+		&cli.Uint64Flag{
+			Name:    AlertDedupSecondsFlagName,
+			Usage:   "Seconds before a mismatch still halting the monitor is notified of again. 0 to notify once until the mismatch clears",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ALERT_DEDUP_SECONDS"),
+		},
+		&cli.Uint64Flag{
+			Name:    StartupGraceSecondsFlagName,
+			Usage:   "Seconds after startup during which mismatches are logged as warnings without notifying, notifying only those still mismatched afterwards. 0 to disable",
+			EnvVars: opservice.PrefixEnvVar(envVar, "STARTUP_GRACE_SECONDS"),
+		},
+		&cli.Uint64Flag{
+			Name:    MaxSyncWaitTicksFlagName,
+			Usage:   "Number of consecutive loops waiting on a lagging L2 node before escalating to an error",
+			Value:   10,
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAX_SYNC_WAIT_TICKS"),
+		},
 		&cli.StringFlag{
-			Name:     OptimismPortalAddressFlagName,
-			Usage:    "Address of the OptimismPortal contract",
-			EnvVars:  opservice.PrefixEnvVar(envVar, "OPTIMISM_PORTAL"),
+			Name:    L2BehindBehaviorFlagName,
+			Usage:   "Behavior once the L2 node is still behind the output past --max.sync.wait.ticks: 'wait' for it, 'skip' the output, or 'error' failing every loop",
+			Value:   L2BehindBehaviorWait,
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_BEHIND_BEHAVIOR"),
+		},
+		&cli.Uint64Flag{
+			Name:    MaxOutputGapSecondsFlagName,
+			Usage:   "Number of seconds without a new output before the proposer is considered stalled. 0 to disable",
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAX_OUTPUT_GAP_SECONDS"),
+		},
+		&cli.Uint64Flag{
+			Name:    MaxStuckTicksFlagName,
+			Usage:   "Number of consecutive loops with outputs available where the output index did not advance before the monitor is considered stuck. 0 to disable",
+			Value:   20,
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAX_STUCK_TICKS"),
+		},
+		&cli.Uint64Flag{
+			Name:    WaitLogIntervalSecFlagName,
+			Usage:   "Minimum number of seconds between logs of waiting for the next output, once caught up. 0 to log every loop",
+			Value:   300,
+			EnvVars: opservice.PrefixEnvVar(envVar, "WAIT_LOG_INTERVAL_SECONDS"),
+		},
+		&cli.Uint64Flag{
+			Name:    MaxL2TimeSkewSecFlagName,
+			Usage:   "Number of seconds the latest L2 block time may differ from the local clock before a warning is logged. 0 to disable",
+			Value:   60,
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAX_L2_TIME_SKEW_SECONDS"),
+		},
+		&cli.Uint64Flag{
+			Name:    MaxOutputsPerTickFlagName,
+			Usage:   "Maximum number of outputs sequentially checked within a single loop",
+			Value:   defaultMaxOutputsPerTick,
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAX_OUTPUTS_PER_TICK"),
+		},
+		&cli.Uint64Flag{
+			Name:    CatchUpThresholdFlagName,
+			Usage:   "Number of outputs lagging behind, above which outputs are checked concurrently to catch up. 0 to disable",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CATCHUP_THRESHOLD"),
+		},
+		&cli.Uint64Flag{
+			Name:    MaxConcurrencyFlagName,
+			Usage:   "Maximum number of outputs checked concurrently when catching up",
+			Value:   defaultMaxConcurrency,
+			EnvVars: opservice.PrefixEnvVar(envVar, "MAX_CONCURRENCY"),
+		},
+		&cli.Uint64Flag{
+			Name:    CatchUpProgressIntervalFlagName,
+			Usage:   "Number of outputs checked between logs of the catch-up progress and rate. 0 to disable",
+			Value:   100,
+			EnvVars: opservice.PrefixEnvVar(envVar, "CATCHUP_PROGRESS_INTERVAL"),
+		},
+		&cli.Uint64Flag{
+			Name:    SampleEveryNFlagName,
+			Usage:   "Only validate outputs at indexes multiple of N, skipping the rest for a cheaper sampling monitor. 1 to validate every output",
+			Value:   1,
+			EnvVars: opservice.PrefixEnvVar(envVar, "SAMPLE_EVERY_N"),
+		},
+		&cli.Uint64Flag{
+			Name:    WindowRefreshTicksFlagName,
+			Usage:   "Number of loops between re-reading the finalization period of outputs. 0 to disable",
+			Value:   60,
+			EnvVars: opservice.PrefixEnvVar(envVar, "WINDOW_REFRESH_TICKS"),
+		},
+		&cli.Uint64Flag{
+			Name:    FinalizationWindowOverrideSecondsFlagName,
+			Usage:   "Finalization period in seconds used in place of the on-chain value, such as on devnets. 0 to use the on-chain value",
+			EnvVars: opservice.PrefixEnvVar(envVar, "FINALIZATION_WINDOW_OVERRIDE_SECONDS"),
+		},
+		&cli.BoolFlag{
+			Name:    DebugReconstructionFlagName,
+			Usage:   "Log the state root, message passer storage root and block hash of every reconstructed output root at debug level",
+			EnvVars: opservice.PrefixEnvVar(envVar, "DEBUG_RECONSTRUCTION"),
+		},
+		&cli.StringFlag{
+			Name:    ProofBlockTagFlagName,
+			Usage:   "Block tag the message passer storage proof is requested at, either 'number' or 'hash' to pin the proof to the block's hash",
+			Value:   ProofBlockTagNumber,
+			EnvVars: opservice.PrefixEnvVar(envVar, "PROOF_BLOCK_TAG"),
+		},
+		&cli.Uint64Flag{
+			Name:    OutputVersionFlagName,
+			Usage:   "Version of the output roots posted by the chain. Only version 0 is supported, failing startup otherwise",
+			EnvVars: opservice.PrefixEnvVar(envVar, "OUTPUT_VERSION"),
+		},
+		&cli.BoolFlag{
+			Name:    CheckCanonicalBlockFlagName,
+			Usage:   "Cross-check the L2 block of each output is canonical, fetching it back by hash and comparing with its child's parent hash",
+			EnvVars: opservice.PrefixEnvVar(envVar, "CHECK_CANONICAL_BLOCK"),
+		},
+		&cli.BoolFlag{
+			Name:    VerifyProofLocallyFlagName,
+			Usage:   "Verify the account proof of the L2ToL1MessagePasser against the state root of the L2 block, rather than trusting the storage root returned by the node",
+			EnvVars: opservice.PrefixEnvVar(envVar, "VERIFY_PROOF_LOCALLY"),
+		},
+		&cli.BoolFlag{
+			Name:    L2BatchRPCFlagName,
+			Usage:   "Batch the L2 requests reconstructing an output root into two round trips, falling back to sequential requests if unsupported",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_BATCH_RPC"),
+		},
+		&cli.BoolFlag{
+			Name:    FetchBlockByHashFlagName,
+			Usage:   "Fetch the L2 block of each output by hash, resolving the hash from the header at its number, for providers slow to serve blocks by number. Cannot be combined with --l2.batch.rpc",
+			EnvVars: opservice.PrefixEnvVar(envVar, "L2_FETCH_BLOCK_BY_HASH"),
+		},
+		&cli.Int64Flag{
+			Name:    InjectFaultAtIndexFlagName,
+			Usage:   "Report the output at this index as mismatched regardless of its output root, to test alerting end-to-end. -1 to disable",
+			Value:   -1,
+			EnvVars: opservice.PrefixEnvVar(envVar, "INJECT_FAULT_AT_INDEX"),
+		},
+		&cli.StringFlag{
+			Name:    MetricsNamespaceFlagName,
+			Usage:   "Namespace prefixing the name of every metric",
+			Value:   MetricsNamespace,
+			EnvVars: opservice.PrefixEnvVar(envVar, "METRICS_NAMESPACE"),
+		},
+		&cli.StringFlag{
+			Name:    MetricsSubsystemFlagName,
+			Usage:   "Subsystem inserted between the namespace and the name of every metric",
+			EnvVars: opservice.PrefixEnvVar(envVar, "METRICS_SUBSYSTEM"),
+		},
+		&cli.StringSliceFlag{
+			Name:    MetricsLabelsFlagName,
+			Usage:   "Constant labels attached to every metric, formatted as name=value, such as network=mainnet",
+			EnvVars: opservice.PrefixEnvVar(envVar, "METRICS_LABELS"),
+		},
+		&cli.BoolFlag{
+			Name:    HealthEnabledFlagName,
+			Usage:   "Enable the health server, serving the /healthz and /readyz probes",
+			EnvVars: opservice.PrefixEnvVar(envVar, "HEALTH_ENABLED"),
+		},
+		&cli.StringFlag{
+			Name:    HealthAddrFlagName,
+			Usage:   "Health server listening address",
+			Value:   "0.0.0.0",
+			EnvVars: opservice.PrefixEnvVar(envVar, "HEALTH_ADDR"),
+		},
+		&cli.IntFlag{
+			Name:    HealthPortFlagName,
+			Usage:   "Health server listening port",
+			Value:   7301,
+			EnvVars: opservice.PrefixEnvVar(envVar, "HEALTH_PORT"),
+		},
+		&cli.BoolFlag{
+			Name:    AdminEnabledFlagName,
+			Usage:   "Serve the /resume and /reset admin endpoints on the health server, resuming validation paused by the circuit breaker and restarting validation from an output index",
+			EnvVars: opservice.PrefixEnvVar(envVar, "ADMIN_ENABLED"),
+		},
+	}
+}
+
+// VerifyRangeCLIFlags are the flags of the one-shot command validating a range of outputs.
+// The range starts at --start.output.index, which must be set explicitly.
+func VerifyRangeCLIFlags(envVar string) []cli.Flag {
+	return append(CLIFlags(envVar), &cli.Uint64Flag{
+		Name:     EndOutputIndexFlagName,
+		Usage:    "Last output index (inclusive) of the range to validate",
+		EnvVars:  opservice.PrefixEnvVar(envVar, "END_OUTPUT_INDEX"),
+		Required: true,
+	})
+}
+
+// FindEarliestMismatchCLIFlags are the flags of the one-shot command scanning backwards for the
+// earliest mismatch. The scan starts at --start.output.index, which must be set explicitly.
+func FindEarliestMismatchCLIFlags(envVar string) []cli.Flag {
+	return append(CLIFlags(envVar), &cli.Uint64Flag{
+		Name:    MinOutputIndexFlagName,
+		Usage:   "Lowest output index to scan back to",
+		EnvVars: opservice.PrefixEnvVar(envVar, "MIN_OUTPUT_INDEX"),
+	})
+}
+
+// ScanHistoryCLIFlags are the flags of the one-shot command validating every posted output,
+// resumable through --checkpoint.path
+func ScanHistoryCLIFlags(envVar string) []cli.Flag {
+	return append(CLIFlags(envVar), &cli.StringFlag{
+		Name:     ReportPathFlagName,
+		Usage:    "Path of a file every mismatched output is appended to as a JSONL record",
+		EnvVars:  opservice.PrefixEnvVar(envVar, "REPORT_PATH"),
+		Required: true,
+	})
+}
+
+// CompareOraclesCLIFlags are the flags of the one-shot command comparing the outputs of two
+// oracles, which only requires l1 nodes. The range starts at --start.output.index, which must
+// be set explicitly.
+func CompareOraclesCLIFlags(envVar string) []cli.Flag {
+	var flags []cli.Flag
+	for _, flag := range CLIFlags(envVar) {
+		switch flag.Names()[0] {
+		case L1NodeURLFlagName, L1RPCHeadersFlagName, L1TLSCertFlagName, L1TLSKeyFlagName, L1TLSCAFlagName, RPCProxyURLFlagName, L2OutputOracleAddressFlagName, StartOutputIndexFlagName:
+			flags = append(flags, flag)
+		}
+	}
+	return append(flags,
+		&cli.StringFlag{
+			Name:    OtherL1NodeURLFlagName,
+			Usage:   "Node URL of the L1 node of the other oracle, if on another chain than --" + L1NodeURLFlagName,
+			EnvVars: opservice.PrefixEnvVar(envVar, "OTHER_L1_NODE_URL"),
+		},
+		&cli.StringFlag{
+			Name:     OtherL2OutputOracleAddressFlagName,
+			Usage:    "Address of the L2OutputOracle contract compared against --" + L2OutputOracleAddressFlagName,
+			EnvVars:  opservice.PrefixEnvVar(envVar, "OTHER_L2_OUTPUT_ORACLE"),
 			Required: true,
 		},
+		&cli.Uint64Flag{
+			Name:     EndOutputIndexFlagName,
+			Usage:    "Last output index (inclusive) of the range to compare",
+			EnvVars:  opservice.PrefixEnvVar(envVar, "END_OUTPUT_INDEX"),
+			Required: true,
+		},
+	)
+}
+
+// OutputRootCLIFlags are the flags of the one-shot command reconstructing the output root of
+// an l2 block, which only requires the l2 node
+func OutputRootCLIFlags(envVar string) []cli.Flag {
+	var flags []cli.Flag
+	for _, flag := range CLIFlags(envVar) {
+		switch flag.Names()[0] {
+		case L2NodeURLFlagName, L2RPCHeadersFlagName, L2TLSCertFlagName, L2TLSKeyFlagName, L2TLSCAFlagName, RPCProxyURLFlagName, MessagePasserAddressFlagName:
+			flags = append(flags, flag)
+		}
 	}
+	return append(flags, &cli.Uint64Flag{
+		Name:     BlockNumberFlagName,
+		Usage:    "Number of the L2 block to reconstruct the output root of",
+		EnvVars:  opservice.PrefixEnvVar(envVar, "BLOCK_NUMBER"),
+		Required: true,
+	})
 }
@@ -0,0 +1,120 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OracleComparison is the result of comparing the outputs of two oracles over a range of indexes
+type OracleComparison struct {
+	StartIndex  uint64             `json:"start_index"`
+	EndIndex    uint64             `json:"end_index"`
+	Compared    uint64             `json:"compared"`
+	Divergences []OutputDivergence `json:"divergences"`
+}
+
+// OutputDivergence is an index at which the two oracles hold different outputs
+type OutputDivergence struct {
+	Index              uint64      `json:"index"`
+	OutputRoot         common.Hash `json:"output_root"`
+	OtherOutputRoot    common.Hash `json:"other_output_root"`
+	L2BlockNumber      uint64      `json:"l2_block_number"`
+	OtherL2BlockNumber uint64      `json:"other_l2_block_number"`
+}
+
+// CompareOracles compares the outputs of two oracles over the inclusive range of indexes, such as
+// before and after a migration. Outputs diverge if either their output root or l2 block number
+// differ, while their timestamps are expected to differ as they were proposed separately.
+func CompareOracles(ctx context.Context, oracle, other outputSource, start, end uint64) (*OracleComparison, error) {
+	if start > end {
+		return nil, fmt.Errorf("start index %d is after end index %d", start, end)
+	}
+
+	callOpts := &bind.CallOpts{Context: ctx}
+	for _, o := range []struct {
+		name   string
+		source outputSource
+	}{{"oracle", oracle}, {"other oracle", other}} {
+		nextOutputIndex, err := o.source.NextOutputIndex(callOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query next output index of the %s: %w", o.name, err)
+		}
+		if end >= nextOutputIndex.Uint64() {
+			return nil, fmt.Errorf("end index %d has not been posted to the %s, next output index is %d", end, o.name, nextOutputIndex)
+		}
+	}
+
+	comparison := &OracleComparison{StartIndex: start, EndIndex: end, Divergences: []OutputDivergence{}}
+	for index := start; index <= end; index++ {
+		output, err := oracle.GetL2Output(callOpts, new(big.Int).SetUint64(index))
+		if err != nil {
+			return comparison, fmt.Errorf("failed to query output %d of the oracle: %w", index, err)
+		}
+		otherOutput, err := other.GetL2Output(callOpts, new(big.Int).SetUint64(index))
+		if err != nil {
+			return comparison, fmt.Errorf("failed to query output %d of the other oracle: %w", index, err)
+		}
+
+		comparison.Compared++
+		if output.OutputRoot != otherOutput.OutputRoot || output.L2BlockNumber.Cmp(otherOutput.L2BlockNumber) != 0 {
+			comparison.Divergences = append(comparison.Divergences, OutputDivergence{
+				Index:              index,
+				OutputRoot:         output.OutputRoot,
+				OtherOutputRoot:    otherOutput.OutputRoot,
+				L2BlockNumber:      output.L2BlockNumber.Uint64(),
+				OtherL2BlockNumber: otherOutput.L2BlockNumber.Uint64(),
+			})
+		}
+	}
+	return comparison, nil
+}
+
+// CompareOraclesFromConfig dials the l1 nodes of both oracles and compares their outputs over the configured range
+func CompareOraclesFromConfig(ctx context.Context, cfg CompareOraclesCLIConfig) (*OracleComparison, error) {
+	tlsConfig, err := newClientTLSConfig(cfg.L1TLSCert, cfg.L1TLSKey, cfg.L1TLSCA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure l1 tls: %w", err)
+	}
+	proxy, err := parseProxyURL(cfg.RPCProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure rpc proxy: %w", err)
+	}
+	client, err := dialClient(ctx, cfg.L1NodeURL, cfg.L1RPCHeaders, tlsConfig, proxy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l1: %w", err)
+	}
+	defer client.Close()
+
+	otherClient := client
+	if cfg.OtherL1NodeURL != "" {
+		if otherClient, err = dialClient(ctx, cfg.OtherL1NodeURL, cfg.L1RPCHeaders, tlsConfig, proxy); err != nil {
+			return nil, fmt.Errorf("failed to dial other l1: %w", err)
+		}
+		defer otherClient.Close()
+	}
+
+	if err := checkContractCode(ctx, client, "L2OutputOracle", cfg.OracleAddress); err != nil {
+		return nil, err
+	}
+	if err := checkContractCode(ctx, otherClient, "other L2OutputOracle", cfg.OtherOracleAddress); err != nil {
+		return nil, err
+	}
+
+	oracle, err := bindings.NewL2OutputOracleCaller(cfg.OracleAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+	}
+	otherOracle, err := bindings.NewL2OutputOracleCaller(cfg.OtherOracleAddress, otherClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to the other L2OutputOracle: %w", err)
+	}
+	outputs := &l2OutputOracleOutputs{L2OutputOracleCaller: oracle, address: cfg.OracleAddress, logs: client}
+	otherOutputs := &l2OutputOracleOutputs{L2OutputOracleCaller: otherOracle, address: cfg.OtherOracleAddress, logs: otherClient}
+	return CompareOracles(ctx, outputs, otherOutputs, cfg.StartIndex, cfg.EndIndex)
+}
@@ -0,0 +1,104 @@
+package fault
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// applyConfigFile sets the flags listed in the --config file, keyed by flag name, that were
+// not set on the command line or through the environment. The file is read as TOML if its
+// extension is .toml, as YAML otherwise. Lists set every value of slice flags.
+func applyConfigFile(ctx *cli.Context) error {
+	path := ctx.String(ConfigFlagName)
+	if path == "" {
+		return nil
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read --%s: %w", ConfigFlagName, err)
+	}
+
+	// sorted for deterministic errors
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == ConfigFlagName {
+			return fmt.Errorf("--%s cannot be set from the config file", ConfigFlagName)
+		}
+		if ctx.IsSet(name) {
+			continue
+		}
+
+		items, err := configFileValues(values[name])
+		if err != nil {
+			return fmt.Errorf("invalid value of %s in --%s: %w", name, ConfigFlagName, err)
+		}
+		for _, item := range items {
+			if err := ctx.Set(name, item); err != nil {
+				return fmt.Errorf("failed to set %s from --%s: %w", name, ConfigFlagName, err)
+			}
+		}
+	}
+	return nil
+}
+
+func readConfigFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// configFileValues formats a value of the config file as flag values, one per list item
+func configFileValues(value any) ([]string, error) {
+	switch value := value.(type) {
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			scalar, err := configFileScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, scalar)
+		}
+		return items, nil
+	default:
+		scalar, err := configFileScalar(value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{scalar}, nil
+	}
+}
+
+func configFileScalar(value any) (string, error) {
+	switch value := value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return fmt.Sprint(value), nil
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", value)
+	}
+}
@@ -0,0 +1,42 @@
+package fault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	readConfig := func(t *testing.T, file, contents string, args ...string) CLIConfig {
+		path := filepath.Join(t.TempDir(), file)
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+
+		var cfg CLIConfig
+		app := &cli.App{
+			Flags: CLIFlags("FAULT_MON_TEST"),
+			Action: func(ctx *cli.Context) (err error) {
+				cfg, err = ReadCLIFlags(ctx)
+				return err
+			},
+		}
+		require.NoError(t, app.Run(append([]string{"fault", "--" + ConfigFlagName, path}, args...)))
+		return cfg
+	}
+
+	t.Run("Yaml", func(t *testing.T) {
+		cfg := readConfig(t, "config.yaml", "l1.node.url: https://l1.example\noptimismportal.address: \"0xbEb5Fc579115071764c7423A4f12eDde41f106Ed\"\nmax.outputs.per.tick: 10\nmetrics.labels:\n  - region=eu\n  - tier=1\n",
+			"--"+MaxOutputsPerTickFlagName, "3")
+		require.Equal(t, "https://l1.example", cfg.L1NodeURL)
+		require.Equal(t, uint64(3), cfg.MaxOutputsPerTick, "flags take precedence over the file")
+		require.Equal(t, map[string]string{"region": "eu", "tier": "1"}, cfg.MetricsLabels)
+	})
+
+	t.Run("Toml", func(t *testing.T) {
+		cfg := readConfig(t, "config.toml", "\"l1.node.url\" = \"https://l1.example\"\n\"optimismportal.address\" = \"0xbEb5Fc579115071764c7423A4f12eDde41f106Ed\"\n\"continue.on.mismatch\" = true\n")
+		require.Equal(t, "https://l1.example", cfg.L1NodeURL)
+		require.True(t, cfg.ContinueOnMismatch)
+	})
+}
@@ -0,0 +1,116 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/faultproof_withdrawals/bindings/dispute"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/faultproof_withdrawals/bindings/l1"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// gameStatusChallengerWins is the status of a game resolved with its root claim disproven
+const gameStatusChallengerWins = 1
+
+// disputeGameOutputs presents the games created by a DisputeGameFactory as outputs, indexed
+// by their position in the factory. The root claim of each game is the proposed output root.
+// Games resolved in favor of the challenger or blacklisted by the portal are invalidated,
+// and presented as such rather than as outputs to check.
+type disputeGameOutputs struct {
+	l1Client bind.ContractCaller
+	portal   *l1.OptimismPortal2Caller
+	factory  *dispute.DisputeGameFactoryCaller
+}
+
+// FinalizationPeriodSeconds returns the portal's proof maturity delay, after which
+// withdrawals proven against a game can be finalized
+func (d *disputeGameOutputs) FinalizationPeriodSeconds(opts *bind.CallOpts) (*big.Int, error) {
+	return d.portal.ProofMaturityDelaySeconds(opts)
+}
+
+func (d *disputeGameOutputs) NextOutputIndex(opts *bind.CallOpts) (*big.Int, error) {
+	return d.factory.GameCount(opts)
+}
+
+func (d *disputeGameOutputs) GetL2Output(opts *bind.CallOpts, index *big.Int) (bindings.TypesOutputProposal, error) {
+	game, err := d.factory.GameAtIndex(opts, index)
+	if err != nil {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("failed to query game: %w", err)
+	}
+
+	gameCaller, err := dispute.NewFaultDisputeGameCaller(game.Proxy, d.l1Client)
+	if err != nil {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("failed to bind to game %s: %w", game.Proxy, err)
+	}
+	status, err := gameCaller.Status(opts)
+	if err != nil {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("failed to query status of game %s: %w", game.Proxy, err)
+	}
+	if status == gameStatusChallengerWins {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("%w: game %s resolved in favor of the challenger", errOutputInvalidated, game.Proxy)
+	}
+	blacklisted, err := d.portal.DisputeGameBlacklist(opts, game.Proxy)
+	if err != nil {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("failed to query blacklist for game %s: %w", game.Proxy, err)
+	}
+	if blacklisted {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("%w: game %s is blacklisted", errOutputInvalidated, game.Proxy)
+	}
+
+	rootClaim, err := gameCaller.RootClaim(opts)
+	if err != nil {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("failed to query root claim of game %s: %w", game.Proxy, err)
+	}
+	l2BlockNumber, err := gameCaller.L2BlockNumber(opts)
+	if err != nil {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("failed to query l2 block number of game %s: %w", game.Proxy, err)
+	}
+
+	return bindings.TypesOutputProposal{
+		OutputRoot:    rootClaim,
+		Timestamp:     new(big.Int).SetUint64(game.Timestamp),
+		L2BlockNumber: l2BlockNumber,
+	}, nil
+}
+
+// Proposer returns the creator of the game at the index
+func (d *disputeGameOutputs) Proposer(opts *bind.CallOpts, index *big.Int) (common.Address, error) {
+	game, err := d.factory.GameAtIndex(opts, index)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to query game: %w", err)
+	}
+
+	gameCaller, err := dispute.NewFaultDisputeGameCaller(game.Proxy, d.l1Client)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to bind to game %s: %w", game.Proxy, err)
+	}
+	return gameCaller.GameCreator(opts)
+}
+
+// bindDisputeGames binds to the DisputeGameFactory of a fault proof portal
+func (m *Monitor) bindDisputeGames(ctx context.Context, portalAddress common.Address) error {
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	optimismPortal, err := l1.NewOptimismPortal2Caller(portalAddress, m.l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind to the OptimismPortal2: %w", err)
+	}
+	factoryAddress, err := optimismPortal.DisputeGameFactory(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query DisputeGameFactory address: %w", err)
+	}
+	m.log.Info("configured DisputeGameFactory", "address", factoryAddress.String())
+
+	factory, err := dispute.NewDisputeGameFactoryCaller(factoryAddress, m.l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind to the DisputeGameFactory: %w", err)
+	}
+
+	m.outputs = &disputeGameOutputs{l1Client: m.l1Client, portal: optimismPortal, factory: factory}
+	m.outputsContract, m.outputsAddress = "DisputeGameFactory", factoryAddress
+	return nil
+}
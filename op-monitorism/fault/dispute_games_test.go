@@ -0,0 +1,85 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/faultproof_withdrawals/bindings/dispute"
+	"github.com/ethereum-optimism/monitorism/op-monitorism/faultproof_withdrawals/bindings/l1"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testContractCaller serves the results of contract calls by the contract and method called
+type testContractCaller struct {
+	abis    map[common.Address]*abi.ABI
+	results map[common.Address]map[string][]any
+}
+
+func (c *testContractCaller) CodeAt(_ context.Context, _ common.Address, _ *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (c *testContractCaller) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	contract, ok := c.abis[*call.To]
+	if !ok {
+		return nil, errors.New("no contract deployed")
+	}
+	method, err := contract.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	results, ok := c.results[*call.To][method.Name]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return method.Outputs.Pack(results...)
+}
+
+func TestDisputeGameOutputs(t *testing.T) {
+	portalABI, err := l1.OptimismPortal2MetaData.GetAbi()
+	require.NoError(t, err)
+	factoryABI, err := dispute.DisputeGameFactoryMetaData.GetAbi()
+	require.NoError(t, err)
+	gameABI, err := dispute.FaultDisputeGameMetaData.GetAbi()
+	require.NoError(t, err)
+
+	portal, factory, game := common.Address{0x1}, common.Address{0x2}, common.Address{0x3}
+	rootClaim := common.Hash{0xaa}
+	newOutputs := func(status uint8, blacklisted bool) *disputeGameOutputs {
+		caller := &testContractCaller{
+			abis: map[common.Address]*abi.ABI{portal: portalABI, factory: factoryABI, game: gameABI},
+			results: map[common.Address]map[string][]any{
+				portal:  {"disputeGameBlacklist": {blacklisted}},
+				factory: {"gameAtIndex": {uint32(0), uint64(1_000_000), game}},
+				game:    {"status": {status}, "rootClaim": {rootClaim}, "l2BlockNumber": {big.NewInt(100)}},
+			},
+		}
+		portalCaller, err := l1.NewOptimismPortal2Caller(portal, caller)
+		require.NoError(t, err)
+		factoryCaller, err := dispute.NewDisputeGameFactoryCaller(factory, caller)
+		require.NoError(t, err)
+		return &disputeGameOutputs{l1Client: caller, portal: portalCaller, factory: factoryCaller}
+	}
+
+	// games in progress or defended are checked
+	for _, status := range []uint8{0, 2} {
+		output, err := newOutputs(status, false).GetL2Output(nil, big.NewInt(0))
+		require.NoError(t, err)
+		require.Equal(t, [32]byte(rootClaim), output.OutputRoot)
+		require.Equal(t, big.NewInt(100), output.L2BlockNumber)
+		require.Equal(t, big.NewInt(1_000_000), output.Timestamp)
+	}
+
+	// defeated or blacklisted games are invalidated, without retrying
+	_, err = newOutputs(gameStatusChallengerWins, false).GetL2Output(nil, big.NewInt(0))
+	require.ErrorIs(t, err, errOutputInvalidated)
+	require.True(t, isDeterministic(err))
+	_, err = newOutputs(2, true).GetL2Output(nil, big.NewInt(0))
+	require.ErrorIs(t, err, errOutputInvalidated)
+}
@@ -0,0 +1,35 @@
+package fault
+
+import (
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// seenOutputRootsSize bounds the recently checked output roots kept to detect duplicates
+const seenOutputRootsSize = 1024
+
+// seenOutput is the output a root was last seen posted at
+type seenOutput struct {
+	index         uint64
+	l2BlockNumber uint64
+}
+
+// trackDuplicateOutputRoot warns of an output root already posted at a different index for a
+// different l2 block, as from a proposer bug or a replayed proposal. The output is still
+// validated on its own, this only flags the anomaly.
+func (m *Monitor) trackDuplicateOutputRoot(index uint64, output bindings.TypesOutputProposal) {
+	if m.seenOutputRoots == nil {
+		return
+	}
+
+	root := common.Hash(output.OutputRoot)
+	l2BlockNumber := output.L2BlockNumber.Uint64()
+	if value, ok := m.seenOutputRoots.Get(root); ok {
+		seen := value.(seenOutput)
+		if seen.index != index && seen.l2BlockNumber != l2BlockNumber {
+			m.log.Warn("output root already posted at a different index", "index", index, "l2_block_number", l2BlockNumber, "output_root", root.String(), "seen_index", seen.index, "seen_l2_block_number", seen.l2BlockNumber)
+			m.duplicateOutputRoot.Inc()
+		}
+	}
+	m.seenOutputRoots.Add(root, seenOutput{index: index, l2BlockNumber: l2BlockNumber})
+}
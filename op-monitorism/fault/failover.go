@@ -0,0 +1,134 @@
+package fault
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// l1FailoverThreshold is the number of consecutive failures reaching
+// the active l1 endpoint before rotating to the next
+const l1FailoverThreshold = 3
+
+// failoverClient is a contract caller over redundant l1 endpoints. Calls are made against
+// the active endpoint, rotating to the next one once it repeatedly fails to respond.
+type failoverClient struct {
+	log     log.Logger
+	clients []*ethclient.Client
+
+	active   atomic.Uint64
+	failures atomic.Uint64
+}
+
+// dialFailoverClient dials every endpoint of the comma-separated list of urls, sending
+// the headers with every request
+func dialFailoverClient(ctx context.Context, log log.Logger, urls string, headers http.Header, tlsConfig *tls.Config, proxy *url.URL) (*failoverClient, error) {
+	f := &failoverClient{log: log}
+	for i, endpoint := range strings.Split(urls, ",") {
+		client, err := dialClient(ctx, strings.TrimSpace(endpoint), headers, tlsConfig, proxy)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to dial l1 endpoint %d: %w", i, err)
+		}
+		f.clients = append(f.clients, client)
+	}
+
+	if len(f.clients) > 1 {
+		log.Info("configured l1 endpoints for failover", "endpoints", len(f.clients))
+	}
+	return f, nil
+}
+
+// activeIndex returns the index of the endpoint calls are currently made against
+func (f *failoverClient) activeIndex() uint64 {
+	return f.active.Load()
+}
+
+// activeClient returns the client of the endpoint calls are currently made against
+func (f *failoverClient) activeClient() *ethclient.Client {
+	return f.clients[f.active.Load()]
+}
+
+func (f *failoverClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	active := f.active.Load()
+	code, err := f.clients[active].CodeAt(ctx, contract, blockNumber)
+	f.record(ctx, active, err)
+	return code, err
+}
+
+func (f *failoverClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	active := f.active.Load()
+	res, err := f.clients[active].CallContract(ctx, call, blockNumber)
+	f.record(ctx, active, err)
+	return res, err
+}
+
+func (f *failoverClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	active := f.active.Load()
+	header, err := f.clients[active].HeaderByNumber(ctx, number)
+	f.record(ctx, active, err)
+	return header, err
+}
+
+func (f *failoverClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	active := f.active.Load()
+	logs, err := f.clients[active].FilterLogs(ctx, q)
+	f.record(ctx, active, err)
+	return logs, err
+}
+
+func (f *failoverClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	active := f.active.Load()
+	tx, isPending, err := f.clients[active].TransactionByHash(ctx, hash)
+	f.record(ctx, active, err)
+	return tx, isPending, err
+}
+
+func (f *failoverClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	active := f.active.Load()
+	sender, err := f.clients[active].TransactionSender(ctx, tx, block, index)
+	f.record(ctx, active, err)
+	return sender, err
+}
+
+// record tracks the outcome of a call against the endpoint, rotating to the next endpoint after
+// consecutive failures. Error responses, such as reverts, show the endpoint is reachable. Calls
+// timing out count as failures of a hung endpoint, while cancelled calls are not held against it.
+func (f *failoverClient) record(ctx context.Context, endpoint uint64, err error) {
+	var rpcErr rpc.Error
+	if err == nil || errors.As(err, &rpcErr) {
+		f.failures.Store(0)
+		return
+	}
+	if errors.Is(ctx.Err(), context.Canceled) || len(f.clients) == 1 {
+		return
+	}
+
+	if f.failures.Add(1) < l1FailoverThreshold {
+		return
+	}
+	next := (endpoint + 1) % uint64(len(f.clients))
+	if f.active.CompareAndSwap(endpoint, next) {
+		f.failures.Store(0)
+		f.log.Warn("l1 endpoint failing, rotating to the next", "endpoint", endpoint, "next_endpoint", next, "err", err)
+	}
+}
+
+func (f *failoverClient) Close() {
+	for _, client := range f.clients {
+		client.Close()
+	}
+}
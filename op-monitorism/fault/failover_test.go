@@ -0,0 +1,80 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// newCallServer serves eth_call with an empty result, or fails every request if unavailable
+func newCallServer(t *testing.T, unavailable bool) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": "0x"})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFailoverClientRotatesOnHungEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(hung.Close)
+	t.Cleanup(func() { close(release) })
+	up := newCallServer(t, false)
+	client, err := dialFailoverClient(context.Background(), testlog.Logger(t, log.LevelDebug), hung.URL+","+up.URL, nil, nil, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	// each attempt bounded by its own timeout, as by withRetries
+	for i := 0; i < l1FailoverThreshold; i++ {
+		require.Equal(t, uint64(0), client.activeIndex())
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := client.CallContract(ctx, ethereum.CallMsg{}, nil)
+		cancel()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+	require.Equal(t, uint64(1), client.activeIndex())
+
+	// cancelled calls are not held against the endpoint
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.record(ctx, 1, context.Canceled)
+	require.Equal(t, uint64(0), client.failures.Load())
+}
+
+func TestFailoverClientRotatesOnRepeatedFailures(t *testing.T) {
+	down, up := newCallServer(t, true), newCallServer(t, false)
+	client, err := dialFailoverClient(context.Background(), testlog.Logger(t, log.LevelDebug), down.URL+", "+up.URL, nil, nil, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < l1FailoverThreshold; i++ {
+		require.Equal(t, uint64(0), client.activeIndex())
+		_, err := client.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.Error(t, err)
+	}
+
+	require.Equal(t, uint64(1), client.activeIndex())
+	_, err = client.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	require.NoError(t, err)
+}
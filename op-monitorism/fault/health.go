@@ -0,0 +1,182 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// healthReporter reports the liveness and state of a monitor
+type healthReporter interface {
+	LastSuccessfulTick() time.Time
+
+	// info returns a JSON encodable snapshot of the monitor's state,
+	// safe to call concurrently with ticks
+	info() any
+
+	// resumeValidation closes the circuit breaker if open
+	resumeValidation()
+
+	// resetValidation restarts validation of the chain from the index, the chain being
+	// empty unless monitoring multiple chains
+	resetValidation(ctx context.Context, chain string, index uint64) error
+}
+
+// healthServer serves the liveness and readiness probes of a monitor. The monitor is
+// ready once its startup completed, and live while it has ticked successfully within
+// the max tick age. Once ready, its state is also served on /info. If admin endpoints are
+// enabled, validation paused by the circuit breaker is resumed with a POST to /resume, and
+// restarted from an index with a POST to /reset.
+type healthServer struct {
+	log   log.Logger
+	clock clock.Clock
+	srv   *httputil.HTTPServer
+
+	// interval of the loop, updated as it changes
+	loopInterval atomic.Int64
+
+	// set once ready
+	reporter atomic.Value
+}
+
+func startHealthServer(log log.Logger, clk clock.Clock, addr string, port int, loopInterval time.Duration, adminEnabled bool) (*healthServer, error) {
+	if clk == nil {
+		clk = clock.SystemClock
+	}
+	h := &healthServer{log: log, clock: clk}
+	h.setLoopInterval(loopInterval)
+
+	log.Info("starting health server", "host", addr, "port", port, "admin", adminEnabled)
+	srv, err := httputil.StartHTTPServer(net.JoinHostPort(addr, strconv.Itoa(port)), h.handler(adminEnabled))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start health server: %w", err)
+	}
+
+	h.srv = srv
+	return h, nil
+}
+
+// handler routes the probes, and the admin endpoints changing the state of the monitor
+// only if enabled, as the health listener is reachable by anyone able to probe it
+func (h *healthServer) handler(adminEnabled bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.handleHealthz)
+	mux.HandleFunc("/readyz", h.handleReadyz)
+	mux.HandleFunc("/info", h.handleInfo)
+	if adminEnabled {
+		mux.HandleFunc("/resume", h.handleResume)
+		mux.HandleFunc("/reset", h.handleReset)
+	}
+	return mux
+}
+
+// setLoopInterval updates the loop interval the max tick age is derived from, such as when
+// reloaded without a restart
+func (h *healthServer) setLoopInterval(interval time.Duration) {
+	h.loopInterval.Store(int64(interval))
+}
+
+// maxTickAge allows for a loop to be missed before the monitor is considered unhealthy
+func (h *healthServer) maxTickAge() time.Duration {
+	return 2 * time.Duration(h.loopInterval.Load())
+}
+
+// markReady marks the startup as complete, with liveness reported by the monitor
+func (h *healthServer) markReady(reporter healthReporter) {
+	h.reporter.Store(reporter)
+}
+
+func (h *healthServer) handleReadyz(w http.ResponseWriter, _ *http.Request) {
+	if h.reporter.Load() == nil {
+		http.Error(w, "starting up", http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (h *healthServer) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	reporter, ok := h.reporter.Load().(healthReporter)
+	if !ok {
+		http.Error(w, "starting up", http.StatusServiceUnavailable)
+		return
+	}
+
+	lastTick := reporter.LastSuccessfulTick()
+	if lastTick.IsZero() {
+		http.Error(w, "no successful tick", http.StatusServiceUnavailable)
+		return
+	}
+	if age := h.clock.Since(lastTick); age > h.maxTickAge() {
+		http.Error(w, fmt.Sprintf("last successful tick %s ago", age.Round(time.Second)), http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (h *healthServer) handleInfo(w http.ResponseWriter, _ *http.Request) {
+	reporter, ok := h.reporter.Load().(healthReporter)
+	if !ok {
+		http.Error(w, "starting up", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reporter.info()); err != nil {
+		h.log.Warn("failed to write info response", "err", err)
+	}
+}
+
+func (h *healthServer) handleResume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	reporter, ok := h.reporter.Load().(healthReporter)
+	if !ok {
+		http.Error(w, "starting up", http.StatusServiceUnavailable)
+		return
+	}
+
+	reporter.resumeValidation()
+	_, _ = w.Write([]byte("ok"))
+}
+
+// handleReset restarts validation from the index query parameter, of the chain query
+// parameter when monitoring multiple chains
+func (h *healthServer) handleReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	reporter, ok := h.reporter.Load().(healthReporter)
+	if !ok {
+		http.Error(w, "starting up", http.StatusServiceUnavailable)
+		return
+	}
+	index, err := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid index", http.StatusBadRequest)
+		return
+	}
+
+	if err := reporter.resetValidation(r.Context(), r.URL.Query().Get("chain"), index); err != nil {
+		h.log.Warn("failed to reset validation", "index", index, "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (h *healthServer) close() error {
+	return h.srv.Close()
+}
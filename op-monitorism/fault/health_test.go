@@ -0,0 +1,141 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHealthReporter struct {
+	lastTick   time.Time
+	resumed    bool
+	resetIndex uint64
+}
+
+func (f *fakeHealthReporter) LastSuccessfulTick() time.Time {
+	return f.lastTick
+}
+
+func (f *fakeHealthReporter) info() any {
+	return MonitorInfo{CurrOutputIndex: 7}
+}
+
+func (f *fakeHealthReporter) resumeValidation() {
+	f.resumed = true
+}
+
+func (f *fakeHealthReporter) resetValidation(_ context.Context, chain string, index uint64) error {
+	if chain != "" {
+		return fmt.Errorf("unknown chain %q", chain)
+	}
+	f.resetIndex = index
+	return nil
+}
+
+func newTestHealthServer(t *testing.T) *healthServer {
+	h := &healthServer{log: testlog.Logger(t, log.LevelDebug), clock: clock.NewDeterministicClock(time.Unix(1700000000, 0))}
+	h.setLoopInterval(30 * time.Second)
+	return h
+}
+
+func TestHealthServerProbes(t *testing.T) {
+	h := newTestHealthServer(t)
+	probe := func(handler http.HandlerFunc) int {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+
+	// starting up
+	require.Equal(t, http.StatusServiceUnavailable, probe(h.handleReadyz))
+	require.Equal(t, http.StatusServiceUnavailable, probe(h.handleHealthz))
+	require.Equal(t, http.StatusServiceUnavailable, probe(h.handleInfo))
+
+	reporter := &fakeHealthReporter{}
+	h.markReady(reporter)
+	require.Equal(t, http.StatusOK, probe(h.handleReadyz))
+	require.Equal(t, http.StatusOK, probe(h.handleInfo))
+	require.Equal(t, http.StatusServiceUnavailable, probe(h.handleHealthz))
+
+	reporter.lastTick = h.clock.Now()
+	require.Equal(t, http.StatusOK, probe(h.handleHealthz))
+
+	reporter.lastTick = h.clock.Now().Add(-2 * time.Minute)
+	require.Equal(t, http.StatusServiceUnavailable, probe(h.handleHealthz))
+
+	rec := httptest.NewRecorder()
+	h.handleResume(rec, httptest.NewRequest(http.MethodGet, "/resume", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.False(t, reporter.resumed)
+
+	rec = httptest.NewRecorder()
+	h.handleResume(rec, httptest.NewRequest(http.MethodPost, "/resume", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, reporter.resumed)
+}
+
+func TestHealthServerReset(t *testing.T) {
+	h := newTestHealthServer(t)
+	reporter := &fakeHealthReporter{}
+	h.markReady(reporter)
+	reset := func(method, target string) int {
+		rec := httptest.NewRecorder()
+		h.handleReset(rec, httptest.NewRequest(method, target, nil))
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusMethodNotAllowed, reset(http.MethodGet, "/reset?index=5"))
+	require.Equal(t, http.StatusBadRequest, reset(http.MethodPost, "/reset"))
+	require.Equal(t, http.StatusBadRequest, reset(http.MethodPost, "/reset?index=5&chain=base"))
+	require.Equal(t, http.StatusOK, reset(http.MethodPost, "/reset?index=5"))
+	require.Equal(t, uint64(5), reporter.resetIndex)
+}
+
+func TestHealthServerAdminEndpoints(t *testing.T) {
+	h := newTestHealthServer(t)
+	reporter := &fakeHealthReporter{}
+	h.markReady(reporter)
+	post := func(handler http.Handler, target string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		return rec.Code
+	}
+
+	disabled := h.handler(false)
+	require.Equal(t, http.StatusNotFound, post(disabled, "/resume"))
+	require.Equal(t, http.StatusNotFound, post(disabled, "/reset?index=5"))
+	require.False(t, reporter.resumed)
+
+	enabled := h.handler(true)
+	require.Equal(t, http.StatusOK, post(enabled, "/resume"))
+	require.Equal(t, http.StatusOK, post(enabled, "/reset?index=5"))
+	require.True(t, reporter.resumed)
+}
+
+func TestHealthServerReloadedLoopInterval(t *testing.T) {
+	h := newTestHealthServer(t)
+	m := &Monitor{health: h}
+	m.loopIntervalMs.Store(30_000)
+	m.state.Store(&MonitorInfo{LoopIntervalMs: 30_000})
+	reporter := &fakeHealthReporter{lastTick: h.clock.Now().Add(-2 * time.Minute)}
+	h.markReady(reporter)
+	healthz := func() int {
+		rec := httptest.NewRecorder()
+		h.handleHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return rec.Code
+	}
+	require.Equal(t, http.StatusServiceUnavailable, healthz())
+
+	// a tick two minutes ago is on time once the interval is raised
+	m.SetLoopInterval(5 * time.Minute)
+	require.Equal(t, http.StatusOK, healthz())
+	require.Equal(t, uint64(300_000), m.info().(*MonitorInfo).LoopIntervalMs)
+}
@@ -0,0 +1,151 @@
+package fault
+
+import (
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterGoCollector registers the Go runtime collector on the registry, prefixed by the metrics
+// namespace and subsystem of the config and with its labels, so the runtime health is reported
+// along with the monitor's metrics. The metrics.Factory only creates metrics, so the registry
+// backing it is registered on directly.
+func RegisterGoCollector(registry prometheus.Registerer, cfg CLIConfig) error {
+	prefix := MetricsNamespace + "_"
+	if cfg.MetricsNamespace != "" {
+		prefix = cfg.MetricsNamespace + "_"
+	}
+	if cfg.MetricsSubsystem != "" {
+		prefix += cfg.MetricsSubsystem + "_"
+	}
+	registry = prometheus.WrapRegistererWithPrefix(prefix, registry)
+	if len(cfg.MetricsLabels) > 0 {
+		registry = prometheus.WrapRegistererWith(cfg.MetricsLabels, registry)
+	}
+	return registry.Register(collectors.NewGoCollector())
+}
+
+// labeledFactory is a metrics.Factory attaching a fixed set of constant
+// labels to every metric it creates
+type labeledFactory struct {
+	metrics.Factory
+	labels prometheus.Labels
+}
+
+func withConstLabels(factory metrics.Factory, labels prometheus.Labels) metrics.Factory {
+	return &labeledFactory{Factory: factory, labels: labels}
+}
+
+func (f *labeledFactory) constLabels(labels prometheus.Labels) prometheus.Labels {
+	merged := prometheus.Labels{}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	for k, v := range f.labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+func (f *labeledFactory) NewCounter(opts prometheus.CounterOpts) prometheus.Counter {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewCounter(opts)
+}
+
+func (f *labeledFactory) NewCounterVec(opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewCounterVec(opts, labelNames)
+}
+
+func (f *labeledFactory) NewGauge(opts prometheus.GaugeOpts) prometheus.Gauge {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewGauge(opts)
+}
+
+func (f *labeledFactory) NewGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewGaugeVec(opts, labelNames)
+}
+
+func (f *labeledFactory) NewHistogram(opts prometheus.HistogramOpts) prometheus.Histogram {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewHistogram(opts)
+}
+
+func (f *labeledFactory) NewHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *prometheus.HistogramVec {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewHistogramVec(opts, labelNames)
+}
+
+func (f *labeledFactory) NewSummary(opts prometheus.SummaryOpts) prometheus.Summary {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewSummary(opts)
+}
+
+func (f *labeledFactory) NewSummaryVec(opts prometheus.SummaryOpts, labelNames []string) *prometheus.SummaryVec {
+	opts.ConstLabels = f.constLabels(opts.ConstLabels)
+	return f.Factory.NewSummaryVec(opts, labelNames)
+}
+
+// namespacedFactory is a metrics.Factory overriding the namespace and subsystem
+// of every metric it creates, where set
+type namespacedFactory struct {
+	metrics.Factory
+	namespace string
+	subsystem string
+}
+
+func withNamespace(factory metrics.Factory, namespace, subsystem string) metrics.Factory {
+	return &namespacedFactory{Factory: factory, namespace: namespace, subsystem: subsystem}
+}
+
+func (f *namespacedFactory) names(namespace, subsystem string) (string, string) {
+	if f.namespace != "" {
+		namespace = f.namespace
+	}
+	if f.subsystem != "" {
+		subsystem = f.subsystem
+	}
+	return namespace, subsystem
+}
+
+func (f *namespacedFactory) NewCounter(opts prometheus.CounterOpts) prometheus.Counter {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewCounter(opts)
+}
+
+func (f *namespacedFactory) NewCounterVec(opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewCounterVec(opts, labelNames)
+}
+
+func (f *namespacedFactory) NewGauge(opts prometheus.GaugeOpts) prometheus.Gauge {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewGauge(opts)
+}
+
+func (f *namespacedFactory) NewGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewGaugeVec(opts, labelNames)
+}
+
+func (f *namespacedFactory) NewHistogram(opts prometheus.HistogramOpts) prometheus.Histogram {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewHistogram(opts)
+}
+
+func (f *namespacedFactory) NewHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *prometheus.HistogramVec {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewHistogramVec(opts, labelNames)
+}
+
+func (f *namespacedFactory) NewSummary(opts prometheus.SummaryOpts) prometheus.Summary {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewSummary(opts)
+}
+
+func (f *namespacedFactory) NewSummaryVec(opts prometheus.SummaryOpts, labelNames []string) *prometheus.SummaryVec {
+	opts.Namespace, opts.Subsystem = f.names(opts.Namespace, opts.Subsystem)
+	return f.Factory.NewSummaryVec(opts, labelNames)
+}
@@ -0,0 +1,49 @@
+package fault
+
+import (
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithNamespace(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	factory := withNamespace(withConstLabels(metrics.With(registry), prometheus.Labels{"chain": "op"}), "staging", "fault")
+
+	factory.NewGauge(prometheus.GaugeOpts{Namespace: MetricsNamespace, Name: "isCurrentlyMismatched"}).Set(1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Equal(t, "staging_fault_isCurrentlyMismatched", families[0].GetName())
+	require.Equal(t, "chain", families[0].GetMetric()[0].GetLabel()[0].GetName())
+}
+
+func TestRegisterGoCollector(t *testing.T) {
+	// alongside the unprefixed collectors of the binary's registry
+	registry := metrics.NewRegistry()
+	require.NoError(t, RegisterGoCollector(registry, CLIConfig{MetricsLabels: map[string]string{"chain": "op"}}))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	names := make(map[string]bool)
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	require.True(t, names["go_goroutines"])
+	require.True(t, names["fault_detector_go_goroutines"])
+	require.True(t, names["fault_detector_go_memstats_heap_alloc_bytes"])
+}
+
+func TestParseMetricsLabels(t *testing.T) {
+	labels, err := parseMetricsLabels([]string{"network=mainnet", "env=prod=blue"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"network": "mainnet", "env": "prod=blue"}, labels)
+
+	_, err = parseMetricsLabels([]string{"net-work=mainnet"})
+	require.Error(t, err)
+	_, err = parseMetricsLabels([]string{"network"})
+	require.Error(t, err)
+}
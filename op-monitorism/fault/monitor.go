@@ -2,221 +2,2564 @@ package fault
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
 	MetricsNamespace = "fault_detector"
+
+	// delay before searching again for the first unfinalized output after a failed search
+	firstUnfinalizedRetryInterval = 5 * time.Minute
+
+	// defaults of the limits on outputs checked per loop, for configs leaving them unset
+	defaultMaxOutputsPerTick = 1
+	defaultMaxConcurrency    = 8
 )
 
 type Monitor struct {
 	log log.Logger
 
-	l1Client *ethclient.Client
+	l1Client *failoverClient
 	l2Client *ethclient.Client
+	// clients passed in by the caller are left open on close
+	ownsClients bool
+
+	// optional op-node cross-checking reconstructed output roots
+	rollupClient *rpc.Client
+	// optional independent l2 node required to agree with the l2 node
+	l2SecondaryClient *ethclient.Client
+
+	currOutputIndex uint64
+	// refreshed every windowRefreshTicks loops as it may change on upgrades
+	faultProofWindow   atomic.Uint64
+	windowRefreshTicks uint64
+	ticks              uint64
+
+	// oracle parameters determining the l2 block number of each output.
+	// Unset for dispute games, which have no fixed schedule
+	startingBlockNumber uint64
+	submissionInterval  uint64
+	startingTimestamp   uint64
+	l2BlockTime         uint64
+
+	// outputs posted to l1, by the L2OutputOracle or DisputeGameFactory
+	outputs         outputSource
+	outputsContract string
+	outputsAddress  common.Address
+
+	// updated as the loop interval is reloaded
+	loopIntervalMs atomic.Uint64
+
+	// mismatch state. When continuing on mismatch, faulty indexes are
+	// recorded here while the monitor advances to subsequent outputs
+	continueOnMismatch bool
+	mismatchedIndexes  map[uint64]struct{}
+
+	// known and accepted mismatches, advanced past without alerting
+	ignoredMismatchIndexes map[uint64]struct{}
+
+	// validation is paused once this many distinct indexes mismatched in a row, until
+	// resumed. Disabled if zero
+	maxConsecutiveMismatches uint64
+	consecutiveMismatches    uint64
+	circuitOpen              atomic.Bool
+
+	rpcRetries   uint64
+	rpcRetryBase time.Duration
+	// bounds every rpc call attempt, unbounded if zero
+	rpcTimeout time.Duration
+
+	checkpointPath string
+
+	// optional, record of every checked output
+	auditLog *auditLog
+
+	// consecutive checks waiting on the l2 node to sync up to the output
+	l2SyncWaits      atomic.Uint64
+	maxSyncWaitTicks uint64
+	l2BehindBehavior string
+
+	// last increase of the next output index, from which the proposer is considered stalled
+	// after the max output gap
+	lastNextOutputIndex uint64
+	lastOutputTime      time.Time
+	maxOutputGap        time.Duration
+
+	// last log of waiting for the next output, rate limited to the wait log interval while
+	// waiting. Zero once an output is available
+	lastWaitLog     time.Time
+	waitLogInterval time.Duration
+
+	// skew between the local clock and the latest l2 block time warned about, disabled if zero
+	maxL2TimeSkew time.Duration
+
+	// consecutive ticks with outputs available without advancing
+	stuckTicks    uint64
+	maxStuckTicks uint64
+
+	maxOutputsPerTick uint64
+
+	// outputs are checked concurrently when lagging by more than the threshold
+	catchUpThreshold uint64
+	maxConcurrency   uint64
+
+	// catch-up progress is logged every interval of indexes, measured from the last log
+	progressInterval uint64
+	progressIndex    uint64
+	progressTime     time.Time
+
+	// only outputs at multiples of this index are validated, every output if one
+	sampleEveryN uint64
+
+	// storage root of the message passer is committed to by output roots
+	messagePasserAddress common.Address
+
+	// number of re-checks confirming a mismatch before it is recorded
+	mismatchConfirmations uint64
+	l1ConfirmationBlocks  uint64
+
+	verifier OutputVerifier
+	// configured version of the output roots, checked against the rollup node's
+	outputVersion eth.Bytes32
+
+	// time source of the proposer schedule and finalization checks
+	clock clock.Clock
+
+	// log the components of every reconstructed output root
+	debugReconstruction bool
+	proofByNumber       bool
+	checkCanonicalBlock bool
+	verifyProofLocally  bool
+	fetchBlockByHash    bool
+	batchRPC            atomic.Bool
+
+	// recently checked output roots, mapped to the output they were posted at
+	seenOutputRoots *lru.Cache
+
+	// forces a mismatch at the index, to exercise alerting
+	injectFault      bool
+	injectFaultIndex uint64
+
+	// optional, notified of mismatches
+	webhook      *webhook
+	webhookPosts *webhookPoster
+	webhookBatch *webhookBatcher
+	slack        *slackNotifier
+	alertDedup   *alertDedup
+
+	// mismatches are not notified of until the end of the startup grace period, after which
+	// the indexes held back are re-checked and notified of if still mismatched
+	startupGraceEnd time.Time
+	graceSuppressed map[uint64]struct{}
+
+	// unix nano time of the last tick completing without error
+	lastSuccessfulTick atomic.Int64
 
-	currOutputIndex  uint64
-	faultProofWindow uint64
+	// state published after every tick, served on /info
+	state atomic.Pointer[MonitorInfo]
 
-	l2OO *bindings.L2OutputOracleCaller
+	// next output index last seen on l1, and the outcome of the last tick
+	nextKnownIndex uint64
+	lastTick       atomic.Pointer[tickResult]
+
+	// first output index still within the finalization window, advanced every tick once known.
+	// Searched for again no earlier than the retry time after a failed search.
+	firstUnfinalizedIndex   uint64
+	firstUnfinalizedKnown   bool
+	firstUnfinalizedRetryAt time.Time
+
+	// timestamp of the latest l2 block as of the last tick, which outputs are finalized against
+	latestL2Time uint64
+
+	// whether the monitor caught up with the posted outputs as of the last tick, and ever
+	// since startup
+	caughtUp     bool
+	everCaughtUp bool
+
+	// optional server of the liveness and readiness probes
+	health *healthServer
+
+	// set when the oracle's next output index was seen below the current
+	// index, confirmed as an l1 reorg if it persists on the following tick
+	suspectedReorg bool
+
+	// next mismatched index to re-check when continuing on mismatches
+	recheckCursor uint64
+
+	// optional, running ticks as outputs are proposed. Ticks are serialized with runMu
+	// as they also run on every loop
+	subscription *outputSubscription
+	runMu        sync.Mutex
 
 	// metrics
-	highestOutputIndex     *prometheus.GaugeVec
-	isCurrentlyMismatched  prometheus.Gauge
-	nodeConnectionFailures *prometheus.CounterVec
+	highestOutputIndex       *prometheus.GaugeVec
+	outputIndexLag           prometheus.Gauge
+	isCurrentlyMismatched    prometheus.Gauge
+	outputMismatchState      *prometheus.GaugeVec
+	mismatchedOutputIndexes  prometheus.Counter
+	nodeConnectionFailures   *prometheus.CounterVec
+	monitorErrorsTotal       *prometheus.CounterVec
+	rpcRetriesCount          *prometheus.CounterVec
+	rpcCallsTotal            *prometheus.CounterVec
+	rpcErrorsTotal           *prometheus.CounterVec
+	l1Reorgs                 prometheus.Counter
+	outputBlockNumberAnomaly prometheus.Counter
+	duplicateOutputRoot      prometheus.Counter
+	l2BehindSkippedOutputs   prometheus.Counter
+	invalidatedOutputs       prometheus.Counter
+	l2SyncLagBlocks          prometheus.Gauge
+	l2SyncWaitTicks          prometheus.Gauge
+	faultProofWindowSeconds  prometheus.Gauge
+	reconstructionRaces      prometheus.Counter
+	outputValidationDuration prometheus.Histogram
+	outputValidationDelay    prometheus.Histogram
+	activeL1Endpoint         prometheus.Gauge
+	secondsUntilFinalization prometheus.Gauge
+	rollupOutputMismatch     prometheus.Counter
+	proposalsByProposer      *prometheus.CounterVec
+	emptyProofResponses      prometheus.Counter
+	invalidAccountProofs     prometheus.Counter
+	unavailableStateRoot     prometheus.Counter
+	zeroOutputData           prometheus.Counter
+	ignoredMismatches        prometheus.Counter
+	outputsValidatedTotal    prometheus.Counter
+	unsampledOutputs         prometheus.Counter
+	lastMismatch             *prometheus.GaugeVec
+	secondsSinceLastOutput   prometheus.Gauge
+	proposerStalled          prometheus.Gauge
+
+	indexOutOfRangeCorrections prometheus.Counter
+	l2NodeDisagreement         prometheus.Counter
+	nonCanonicalBlock          prometheus.Counter
+	mismatchRecoveries         prometheus.Counter
+	stuckTicksGauge            prometheus.Gauge
+	monitorStuck               prometheus.Gauge
+	outputSubscriptionActive   prometheus.Gauge
+	outputSubscriptionDrops    prometheus.Counter
+
+	submissionIntervalGauge        prometheus.Gauge
+	l2BlockTimeGauge               prometheus.Gauge
+	secondsUntilNextExpectedOutput prometheus.Gauge
+
+	validatedOutputAgeSeconds prometheus.Gauge
+	l2TimeSkewSeconds         prometheus.Gauge
+	circuitBreakerOpen        prometheus.Gauge
+	unfinalizedOutputs        prometheus.Gauge
+	caughtUpGauge             prometheus.Gauge
+
+	outputFinalizationDeadlineUnix *prometheus.GaugeVec
 }
 
 func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (*Monitor, error) {
-	log.Info("creating fault monitor...")
+	l1TLSConfig, err := cfg.l1TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure l1 tls: %w", err)
+	}
+	l2TLSConfig, err := cfg.l2TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure l2 tls: %w", err)
+	}
+	proxy, err := parseProxyURL(cfg.RPCProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure rpc proxy: %w", err)
+	}
 
-	l1Client, err := ethclient.Dial(cfg.L1NodeURL)
+	l1Client, err := dialFailoverClient(ctx, log, cfg.L1NodeURL, cfg.L1RPCHeaders, l1TLSConfig, proxy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial l1: %w", err)
 	}
-	l2Client, err := ethclient.Dial(cfg.L2NodeURL)
+	l2Client, err := dialClient(ctx, cfg.L2NodeURL, cfg.L2RPCHeaders, l2TLSConfig, proxy)
 	if err != nil {
+		l1Client.Close()
 		return nil, fmt.Errorf("failed to dial l2: %w", err)
 	}
 
-	optimismPortal, err := bindings.NewOptimismPortalCaller(cfg.OptimismPortalAddress, l1Client)
+	monitor, err := newMonitor(ctx, log, m, cfg, l1Client, l2Client, proxy, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
+		l1Client.Close()
+		l2Client.Close()
+		return nil, err
 	}
+	return monitor, nil
+}
 
-	l2OOAddress, err := optimismPortal.L2ORACLE(&bind.CallOpts{Context: ctx})
+// NewMonitorWithClients creates a monitor over already dialed l1 and l2 clients, for embedding
+// the monitor in a service managing its own clients. The node URLs of the config are ignored
+// and the clients are left open when the monitor is closed.
+func NewMonitorWithClients(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig, l1Client, l2Client *ethclient.Client) (*Monitor, error) {
+	proxy, err := parseProxyURL(cfg.RPCProxyURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query L2OO address: %w", err)
+		return nil, fmt.Errorf("failed to configure rpc proxy: %w", err)
 	}
-	log.Info("configured L2OutputOracle", "address", l2OOAddress.String())
+	return newMonitor(ctx, log, m, cfg, &failoverClient{log: log, clients: []*ethclient.Client{l1Client}}, l2Client, proxy, false)
+}
 
-	l2OO, err := bindings.NewL2OutputOracleCaller(l2OOAddress, l1Client)
-	if err != nil {
-		return nil, fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+// newMonitor creates the monitor over the clients, closing them along with the
+// monitor if owned. Any other client is dialed through the proxy, if set.
+func newMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig, l1Client *failoverClient, l2Client *ethclient.Client, proxy *url.URL, ownsClients bool) (_ *Monitor, err error) {
+	log.Info("creating fault monitor...")
+	if cfg.MetricsNamespace != "" || cfg.MetricsSubsystem != "" {
+		m = withNamespace(m, cfg.MetricsNamespace, cfg.MetricsSubsystem)
 	}
-	faultProofWindow, err := l2OO.FinalizationPeriodSeconds(&bind.CallOpts{Context: ctx})
-	if err != nil {
-		return nil, fmt.Errorf("failed to query for finalization window: %w", err)
+	if len(cfg.MetricsLabels) > 0 {
+		m = withConstLabels(m, cfg.MetricsLabels)
+	}
+
+	// started first to report the monitor as not ready during startup
+	var health *healthServer
+	if cfg.HealthEnabled {
+		health, err = startHealthServer(log, cfg.Clock, cfg.HealthAddr, cfg.HealthPort, time.Duration(cfg.LoopIntervalMs)*time.Millisecond, cfg.AdminEnabled)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				_ = health.close()
+			}
+		}()
+	}
+
+	// A node of the wrong network would flag every output as mismatched
+	for _, client := range l1Client.clients {
+		if err := checkChainID(ctx, client, "l1", cfg.L1ChainID); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkChainID(ctx, l2Client, "l2", cfg.L2ChainID); err != nil {
+		return nil, err
 	}
 
 	monitor := &Monitor{
 		log: log,
 
-		l1Client: l1Client,
-		l2Client: l2Client,
+		l1Client:    l1Client,
+		l2Client:    l2Client,
+		ownsClients: ownsClients,
+
+		continueOnMismatch:    cfg.ContinueOnMismatch,
+		mismatchConfirmations: cfg.MismatchConfirmations,
+		l1ConfirmationBlocks:  cfg.L1ConfirmationBlocks,
+		mismatchedIndexes:     make(map[uint64]struct{}),
+
+		ignoredMismatchIndexes: make(map[uint64]struct{}),
+
+		maxConsecutiveMismatches: cfg.MaxConsecutiveMismatches,
+
+		rpcRetries:   cfg.RPCRetries,
+		rpcRetryBase: time.Duration(cfg.RPCRetryBaseMs) * time.Millisecond,
+		rpcTimeout:   time.Duration(cfg.RPCTimeoutMs) * time.Millisecond,
+
+		checkpointPath: cfg.CheckpointPath,
+
+		maxSyncWaitTicks: cfg.MaxSyncWaitTicks,
+		l2BehindBehavior: cfg.L2BehindBehavior,
+		maxOutputGap:     time.Duration(cfg.MaxOutputGapSeconds) * time.Second,
+		waitLogInterval:  time.Duration(cfg.WaitLogIntervalSec) * time.Second,
+		maxL2TimeSkew:    time.Duration(cfg.MaxL2TimeSkewSec) * time.Second,
+		maxStuckTicks:    cfg.MaxStuckTicks,
+
+		windowRefreshTicks: cfg.WindowRefreshTicks,
 
-		l2OO:             l2OO,
-		faultProofWindow: faultProofWindow.Uint64(),
+		messagePasserAddress: predeploys.L2ToL1MessagePasserAddr,
+		verifier:             cfg.OutputVerifier,
+		clock:                cfg.Clock,
+		debugReconstruction:  cfg.DebugReconstruction,
+		proofByNumber:        cfg.ProofBlockTag != ProofBlockTagHash,
+		checkCanonicalBlock:  cfg.CheckCanonicalBlock,
+		verifyProofLocally:   cfg.VerifyProofLocally,
+		fetchBlockByHash:     cfg.FetchBlockByHash,
+
+		maxOutputsPerTick: cfg.MaxOutputsPerTick,
+
+		catchUpThreshold: cfg.CatchUpThreshold,
+		maxConcurrency:   cfg.MaxConcurrency,
+		progressInterval: cfg.CatchUpProgressInterval,
+		sampleEveryN:     cfg.SampleEveryN,
 
 		highestOutputIndex: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "highestOutputIndex",
 			Help:      "Highest output indicies (checked and known)",
 		}, []string{"type"}),
+		outputIndexLag: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputIndexLag",
+			Help:      "number of posted outputs not yet checked",
+		}),
 		isCurrentlyMismatched: m.NewGauge(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "isCurrentlyMismatched",
 			Help:      "0 if state is ok, 1 if state is mismatched",
 		}),
+		outputMismatchState: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputMismatchState",
+			Help:      "1 for every output index currently mismatched, removed once cleared",
+		}, []string{"index"}),
+		mismatchedOutputIndexes: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "mismatchedOutputIndexes",
+			Help:      "number of distinct output indicies seen with a mismatched output root",
+		}),
 		nodeConnectionFailures: m.NewCounterVec(prometheus.CounterOpts{
 			Namespace: MetricsNamespace,
 			Name:      "nodeConnectionFailures",
 			Help:      "number of times node connection has failed",
 		}, []string{"layer", "section"}),
+		monitorErrorsTotal: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "monitorErrorsTotal",
+			Help:      "number of loops failed, by class of the error: network, contract, data or unknown",
+		}, []string{"class"}),
+		rpcRetriesCount: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "rpcRetries",
+			Help:      "number of times a failed rpc call has been retried",
+		}, []string{"layer", "section"}),
+		rpcCallsTotal: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "rpcCallsTotal",
+			Help:      "number of rpc calls made, including retries",
+		}, []string{"endpoint", "method"}),
+		rpcErrorsTotal: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "rpcErrorsTotal",
+			Help:      "number of rpc calls that failed, including retries",
+		}, []string{"endpoint", "method"}),
+		l1Reorgs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l1Reorgs",
+			Help:      "number of l1 reorgs detected that removed posted outputs",
+		}),
+		outputBlockNumberAnomaly: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputBlockNumberAnomaly",
+			Help:      "number of outputs checked with an l2 block number off the oracle's submission interval",
+		}),
+		l2BehindSkippedOutputs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l2BehindSkippedOutputs",
+			Help:      "number of outputs skipped without validation as the l2 node was stuck behind them",
+		}),
+		invalidatedOutputs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invalidatedOutputs",
+			Help:      "number of outputs skipped without validation as they were invalidated on l1, by a dispute game resolved in favor of the challenger or blacklisted",
+		}),
+		duplicateOutputRoot: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "duplicateOutputRoot",
+			Help:      "number of outputs checked with an output root recently posted at a different index and l2 block",
+		}),
+		l2SyncLagBlocks: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l2SyncLagBlocks",
+			Help:      "number of blocks the l2 node is behind the output being checked",
+		}),
+		l2SyncWaitTicks: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l2SyncWaitTicks",
+			Help:      "number of consecutive checks waiting on the l2 node to sync",
+		}),
+		faultProofWindowSeconds: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "faultProofWindowSeconds",
+			Help:      "finalization period of outputs in seconds, as last read from l1",
+		}),
+		reconstructionRaces: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "reconstructionRaces",
+			Help:      "number of output checks skipped as the l2 block was reorged during reconstruction",
+		}),
+		outputValidationDuration: m.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputValidationDurationSeconds",
+			Help:      "duration of fetching and reconstructing an output from l1 and l2",
+			Buckets:   prometheus.ExponentialBucketsRange(0.01, 10, 10),
+		}),
+		outputValidationDelay: m.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputValidationDelaySeconds",
+			Help:      "time between an output being posted on l1 and the monitor checking it",
+			Buckets:   prometheus.ExponentialBucketsRange(10, 7*24*60*60, 12),
+		}),
+		activeL1Endpoint: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "activeL1Endpoint",
+			Help:      "index of the l1 endpoint in use, among the configured endpoints",
+		}),
+		secondsUntilFinalization: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "secondsUntilFinalization",
+			Help:      "seconds remaining until the last checked output becomes finalizable, negative once it is",
+		}),
+		rollupOutputMismatch: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "rollupOutputMismatch",
+			Help:      "number of checks where the rollup node's output root differed from the reconstructed one",
+		}),
+		proposalsByProposer: m.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "proposalsByProposer",
+			Help:      "number of distinct outputs checked by the address that proposed them",
+		}, []string{"proposer"}),
+		emptyProofResponses: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "emptyProofResponses",
+			Help:      "number of proof responses of the message passer missing its storage root",
+		}),
+		invalidAccountProofs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "invalidAccountProofs",
+			Help:      "number of proof responses of the message passer failing to verify against the l2 state root",
+		}),
+		unavailableStateRoot: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unavailableStateRoot",
+			Help:      "number of l2 blocks returned with a zero state root, as by partially-synced nodes",
+		}),
+		ignoredMismatches: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "ignoredMismatches",
+			Help:      "number of mismatches seen at ignored output indexes",
+		}),
+		zeroOutputData: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "zeroOutputData",
+			Help:      "number of output responses with a zero output root or l2 block number",
+		}),
+		outputsValidatedTotal: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputsValidatedTotal",
+			Help:      "number of outputs matching their reconstructed output root since startup",
+		}),
+		unsampledOutputs: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unsampledOutputs",
+			Help:      "number of outputs skipped without validation when sampling every nth output",
+		}),
+		lastMismatch: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "lastMismatch",
+			Help:      "1 labeled with the details of the latest mismatched output, unset once no index remains mismatched",
+		}, []string{"index", "l2_block_number", "expected_output_root", "actual_output_root"}),
+		secondsSinceLastOutput: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "secondsSinceLastOutput",
+			Help:      "seconds since the next output index last increased, measured from startup until it first does",
+		}),
+		proposerStalled: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "proposerStalled",
+			Help:      "0 if new outputs are being posted, 1 if none were for longer than the max output gap",
+		}),
+		indexOutOfRangeCorrections: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "indexOutOfRangeCorrections",
+			Help:      "number of times the output index was clamped back after querying an output out of range",
+		}),
+		l2NodeDisagreement: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l2NodeDisagreement",
+			Help:      "number of checks where the secondary l2 node disagreed with the l2 node",
+		}),
+		nonCanonicalBlock: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "nonCanonicalBlock",
+			Help:      "number of l2 blocks fetched by number found not to be canonical",
+		}),
+		mismatchRecoveries: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "mismatchRecoveries",
+			Help:      "number of previously mismatched indexes found to match on a later check",
+		}),
+		stuckTicksGauge: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "stuckTicks",
+			Help:      "number of consecutive loops with outputs available where the output index did not advance",
+		}),
+		l2TimeSkewSeconds: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l2TimeSkewSeconds",
+			Help:      "local time minus the timestamp of the latest l2 block, in seconds",
+		}),
+		caughtUpGauge: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "caughtUp",
+			Help:      "1 while the monitor has checked every posted output, tracking new proposals, 0 while catching up",
+		}),
+		unfinalizedOutputs: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unfinalizedOutputs",
+			Help:      "number of posted outputs still within the finalization window",
+		}),
+		monitorStuck: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "monitorStuck",
+			Help:      "1 if the output index did not advance for longer than the max stuck ticks despite available outputs",
+		}),
+		outputSubscriptionActive: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputSubscriptionActive",
+			Help:      "1 if subscribed to proposed outputs, 0 if polling only",
+		}),
+		outputSubscriptionDrops: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputSubscriptionDrops",
+			Help:      "number of times the subscription to proposed outputs dropped or failed to subscribe",
+		}),
+		submissionIntervalGauge: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "submissionInterval",
+			Help:      "number of l2 blocks between outputs, as configured on the oracle",
+		}),
+		l2BlockTimeGauge: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "l2BlockTime",
+			Help:      "seconds between l2 blocks, as configured on the oracle",
+		}),
+		secondsUntilNextExpectedOutput: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "secondsUntilNextExpectedOutput",
+			Help:      "seconds until the next output can be proposed, negative once it is overdue",
+		}),
+		validatedOutputAgeSeconds: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "validatedOutputAgeSeconds",
+			Help:      "age of the l2 block of the last checked output, in seconds",
+		}),
+		circuitBreakerOpen: m.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "circuitBreakerOpen",
+			Help:      "1 if validation is paused after too many consecutive mismatches, 0 otherwise",
+		}),
+		outputFinalizationDeadlineUnix: m.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "outputFinalizationDeadlineUnix",
+			Help:      "unix time at which the last checked output becomes finalizable, labelled with its index",
+		}, []string{"index"}),
+	}
+
+	if monitor.clock == nil {
+		monitor.clock = clock.SystemClock
+	}
+	// unset by embedders, no output would be checked or a catch-up would never advance
+	if monitor.maxOutputsPerTick == 0 {
+		monitor.maxOutputsPerTick = defaultMaxOutputsPerTick
+	}
+	if monitor.maxConcurrency == 0 {
+		monitor.maxConcurrency = defaultMaxConcurrency
+	}
+	monitor.loopIntervalMs.Store(cfg.LoopIntervalMs)
+	if monitor.seenOutputRoots, err = lru.New(seenOutputRootsSize); err != nil {
+		return nil, fmt.Errorf("failed to create output root cache: %w", err)
+	}
+
+	if err := monitor.bindOutputs(ctx, cfg.OptimismPortalAddress, cfg.L2OutputOracleAddress); err != nil {
+		return nil, err
+	}
+	faultProofWindow, err := monitor.outputs.FinalizationPeriodSeconds(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query for finalization window: %w", err)
+	}
+	if cfg.FinalizationWindowOverrideSeconds > 0 {
+		log.Warn("overriding the on-chain finalization window", "window", cfg.FinalizationWindowOverrideSeconds, "on_chain_window", faultProofWindow)
+		faultProofWindow = new(big.Int).SetUint64(cfg.FinalizationWindowOverrideSeconds)
+		monitor.windowRefreshTicks = 0
 	}
+	monitor.faultProofWindow.Store(faultProofWindow.Uint64())
+	monitor.faultProofWindowSeconds.Set(float64(faultProofWindow.Uint64()))
 
 	startingOutputIndex := cfg.StartOutputIndex
-	if startingOutputIndex < 0 {
-		firstUnfinalizedIndex, err := monitor.findFirstUnfinalizedOutputIndex(ctx, monitor.faultProofWindow)
+	var resumedCheckpoint bool
+	var checkpointMismatches []uint64
+	var firstUnfinalizedIndex uint64
+	if cfg.StartFromLatest {
+		nextOutputIndex, err := withRetries(ctx, monitor, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+			return monitor.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx})
+		})
 		if err != nil {
+			monitor.nodeConnectionFailures.WithLabelValues("l1", "nextOutputIndex").Inc()
+			return nil, fmt.Errorf("failed to query next output index: %w", err)
+		}
+		log.Warn("starting from the latest output, posted outputs are not validated", "index", nextOutputIndex)
+		startingOutputIndex = nextOutputIndex.Int64()
+	} else if startingOutputIndex < 0 {
+		firstUnfinalizedIndex, err = monitor.findFirstUnfinalizedOutputIndex(ctx, monitor.faultProofWindow.Load())
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("failed to find first unfinalized output index: %w", err)
+			}
+
+			// Rather than failing startup on a flaky node, conservatively start from the
+			// checkpoint if any, otherwise from the first output
 			monitor.nodeConnectionFailures.WithLabelValues("l1", "firstUnfinalizedIndex").Inc()
-			return nil, fmt.Errorf("failed to find first unfinalized output index: %w", err)
+			log.Error("failed to find first unfinalized output index, falling back to the checkpoint or first output", "err", err)
+			firstUnfinalizedIndex = 0
+		} else {
+			monitor.firstUnfinalizedIndex = firstUnfinalizedIndex
+			monitor.firstUnfinalizedKnown = true
 		}
 		startingOutputIndex = int64(firstUnfinalizedIndex)
-	}
 
-	log.Info("configured starting index", "index", startingOutputIndex)
-	monitor.currOutputIndex = uint64(startingOutputIndex)
-	return monitor, nil
-}
+		if cfg.CheckpointPath != "" {
+			checkpoint, mismatched, err := readCheckpoint(cfg.CheckpointPath)
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				log.Info("no checkpoint found", "path", cfg.CheckpointPath)
+			case err != nil:
+				log.Warn("failed to read checkpoint, ignoring", "path", cfg.CheckpointPath, "err", err)
+			case checkpoint > firstUnfinalizedIndex:
+				log.Info("resuming from checkpoint", "path", cfg.CheckpointPath, "index", checkpoint)
+				startingOutputIndex = int64(checkpoint)
+				resumedCheckpoint = true
+				checkpointMismatches = mismatched
+			default:
+				log.Info("checkpoint is not ahead of the first unfinalized output, ignoring", "path", cfg.CheckpointPath, "index", checkpoint)
+			}
+		}
+	}
 
-func (m *Monitor) Run(ctx context.Context) {
-	callOpts := &bind.CallOpts{Context: ctx}
+	// Clients dialed from here are closed should a later step fail
+	if cfg.RollupNodeURL != "" {
+		monitor.rollupClient, err = rpc.DialOptions(ctx, cfg.RollupNodeURL, clientOptions(nil, nil, proxy)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial rollup node: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				monitor.rollupClient.Close()
+			}
+		}()
+		log.Info("cross-checking output roots with the rollup node")
+	}
 
-	// Check for available outputs to validate
+	if cfg.L2NodeURLSecondary != "" {
+		l2TLSConfig, tlsErr := cfg.l2TLSConfig()
+		if tlsErr != nil {
+			return nil, fmt.Errorf("failed to configure l2 tls: %w", tlsErr)
+		}
+		monitor.l2SecondaryClient, err = dialClient(ctx, cfg.L2NodeURLSecondary, cfg.L2RPCHeaders, l2TLSConfig, proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial secondary l2: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				monitor.l2SecondaryClient.Close()
+			}
+		}()
+		if err := checkChainID(ctx, monitor.l2SecondaryClient, "l2 secondary", cfg.L2ChainID); err != nil {
+			return nil, err
+		}
+		log.Info("requiring the secondary l2 node to agree with the l2 node")
+	}
 
-	nextOutputIndex, err := m.l2OO.NextOutputIndex(callOpts)
-	if err != nil {
-		m.log.Error("failed to query next output index", "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l1", "nextOutputIndex").Inc()
-		return
+	if cfg.MessagePasserAddress != (common.Address{}) {
+		monitor.messagePasserAddress = cfg.MessagePasserAddress
 	}
-	if m.currOutputIndex >= nextOutputIndex.Uint64() {
-		m.log.Info("waiting for next output", "index", m.currOutputIndex, "next_index", nextOutputIndex)
-		return
+	if monitor.verifier == nil {
+		if cfg.OutputVersion != 0 {
+			return nil, fmt.Errorf("output version %d is not supported, only version 0 output roots are reconstructed. Embedders can verify other versions with the OutputVerifier of the config", cfg.OutputVersion)
+		}
+		monitor.verifier = OutputV0Verifier{}
 	}
+	monitor.outputVersion = eth.Bytes32(common.BigToHash(new(big.Int).SetUint64(cfg.OutputVersion)))
+	log.Info("configured message passer", "address", monitor.messagePasserAddress.String())
 
-	m.highestOutputIndex.WithLabelValues("known").Set(float64(nextOutputIndex.Int64()))
-	m.log.Info("checking output", "index", m.currOutputIndex)
+	if err := probeL2Methods(ctx, l2Client, "l2", monitor.messagePasserAddress, monitor.proofByNumber); err != nil {
+		return nil, err
+	}
+	if monitor.l2SecondaryClient != nil {
+		if err := probeL2Methods(ctx, monitor.l2SecondaryClient, "l2 secondary", monitor.messagePasserAddress, monitor.proofByNumber); err != nil {
+			return nil, err
+		}
+	}
 
-	// Fetch Output
+	if cfg.AuditLogPath != "" {
+		if monitor.auditLog, err = openAuditLog(cfg.AuditLogPath); err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				_ = monitor.auditLog.close()
+			}
+		}()
+		log.Info("recording checked outputs to audit log", "path", cfg.AuditLogPath)
+	}
 
-	output, err := m.l2OO.GetL2Output(callOpts, big.NewInt(int64(m.currOutputIndex)))
-	if err != nil {
-		m.log.Error("failed to query output", "index", m.currOutputIndex, "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l1", "getL2Output").Inc()
-		return
+	if cfg.WebhookURL != "" {
+		log.Info("posting mismatches to webhook", "batch_ms", cfg.WebhookBatchMs, "batch_size", cfg.WebhookBatchSize, "gzip", cfg.WebhookGzip, "signed", cfg.WebhookSigningSecret != "")
+		monitor.webhook = newWebhook(log, cfg.WebhookURL)
+		monitor.webhook.gzip = cfg.WebhookGzip
+		monitor.webhook.signingSecret = []byte(cfg.WebhookSigningSecret)
+		if cfg.WebhookBatchMs > 0 {
+			monitor.webhookBatch = newWebhookBatcher(log, monitor.webhook, time.Duration(cfg.WebhookBatchMs)*time.Millisecond, int(cfg.WebhookBatchSize))
+			defer func() {
+				if err != nil {
+					monitor.webhookBatch.close(ctx)
+				}
+			}()
+		} else {
+			monitor.webhookPosts = newWebhookPoster(log, monitor.webhook)
+		}
 	}
-	l2Height, err := m.l2Client.BlockNumber(ctx)
-	if err != nil {
-		m.log.Error("failed to query latest l2 height", "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l2", "blockNumber").Inc()
-		return
+	if cfg.SlackWebhookURL != "" {
+		log.Info("posting mismatches to slack")
+		monitor.slack = newSlackNotifier(log, monitor.clock, cfg.SlackWebhookURL)
 	}
-	if l2Height < output.L2BlockNumber.Uint64() {
-		m.log.Warn("l2 node is behind, waiting for sync...")
-		return
+	if monitor.webhook != nil || monitor.slack != nil {
+		monitor.alertDedup = newAlertDedup(monitor.clock, time.Duration(cfg.AlertDedupSeconds)*time.Second)
+	}
+	if cfg.StartupGraceSeconds > 0 {
+		log.Info("holding back mismatch notifications after startup", "grace_seconds", cfg.StartupGraceSeconds)
+		monitor.startupGraceEnd = monitor.clock.Now().Add(time.Duration(cfg.StartupGraceSeconds) * time.Second)
+		monitor.graceSuppressed = make(map[uint64]struct{})
 	}
 
-	// Fetch pre-image information for the output root from L2 to reconstruct
+	if cfg.L2BatchRPC {
+		log.Info("batching l2 requests")
+		monitor.batchRPC.Store(true)
+	}
 
-	block, err := m.l2Client.BlockByNumber(ctx, output.L2BlockNumber)
-	if err != nil {
-		m.log.Error("failed to query l2 block", "height", output.L2BlockNumber, "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l2", "blockByNumber").Inc()
-		return
+	if cfg.InjectFaultAtIndex >= 0 {
+		log.Error("FAULT INJECTION IS ACTIVE. the output at the index will be reported as mismatched regardless of its output root", "index", cfg.InjectFaultAtIndex)
+		monitor.injectFault = true
+		monitor.injectFaultIndex = uint64(cfg.InjectFaultAtIndex)
 	}
-	proof := struct{ StorageHash common.Hash }{}
-	if err := m.l2Client.Client().CallContext(ctx, &proof, "eth_getProof",
-		predeploys.L2ToL1MessagePasserAddr, nil, hexutil.EncodeBig(block.Number())); err != nil {
-		m.log.Error("failed to query for proof response of l2ToL1MP contract", "err", err)
-		m.nodeConnectionFailures.WithLabelValues("l2", "getProof").Inc()
-		return
+
+	if cfg.ContinueOnMismatch {
+		log.Info("continuing on mismatch. faulty outputs will be recorded and skipped")
+	}
+	for _, index := range cfg.IgnoredMismatchIndexes {
+		monitor.ignoredMismatchIndexes[index] = struct{}{}
+	}
+	if len(cfg.IgnoredMismatchIndexes) > 0 {
+		log.Info("ignoring mismatches at known indexes", "indexes", cfg.IgnoredMismatchIndexes)
+	}
+	if cfg.MaxConsecutiveMismatches > 0 {
+		log.Info("pausing validation after consecutive mismatches", "max_consecutive_mismatches", cfg.MaxConsecutiveMismatches)
 	}
 
-	// Reconstruct & verify
+	if resumedCheckpoint && !monitor.verifyCheckpoint(ctx, uint64(startingOutputIndex)) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		startingOutputIndex = int64(firstUnfinalizedIndex)
+		checkpointMismatches = nil
+	}
 
-	outputRoot := eth.OutputRoot(&eth.OutputV0{StateRoot: eth.Bytes32(block.Root()), MessagePasserStorageRoot: eth.Bytes32(proof.StorageHash), BlockHash: block.Hash()})
-	if outputRoot != eth.Bytes32(output.OutputRoot) {
-		m.log.Error("output root mismatch!!!",
-			"index", m.currOutputIndex,
-			"expected_output_root", outputRoot.String(),
-			"actual_output_root", common.Hash(output.OutputRoot).String(),
-			"finalization_time", time.Unix(int64(block.Time()+m.faultProofWindow), 0).String(),
-		)
+	log.Info("configured starting index", "index", startingOutputIndex)
+	monitor.currOutputIndex = uint64(startingOutputIndex)
+	if len(checkpointMismatches) > 0 {
+		monitor.restoreMismatches(ctx, checkpointMismatches)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	monitor.progressIndex, monitor.progressTime = monitor.currOutputIndex, monitor.clock.Now()
+	monitor.publishState()
 
-		m.isCurrentlyMismatched.Set(1)
-		return
+	// subscribed last, as proposed outputs immediately run ticks
+	if cfg.L1WSURL != "" {
+		if err := monitor.subscribeOutputs(ctx, cfg.L1WSURL, cfg); err != nil {
+			return nil, err
+		}
 	}
 
-	// Continue
+	if health != nil {
+		monitor.health = health
+		health.markReady(monitor)
+	}
+	return monitor, nil
+}
 
-	m.log.Info("validated output", "index", m.currOutputIndex, "output_root", outputRoot.String(), "finalization_time", time.Unix(int64(block.Time()+m.faultProofWindow), 0).String())
-	m.highestOutputIndex.WithLabelValues("checked").Set(float64(m.currOutputIndex))
+// checkChainID verifies the client is connected to the expected chain, if configured
+func checkChainID(ctx context.Context, client *ethclient.Client, layer string, expected uint64) error {
+	if expected == 0 {
+		return nil
+	}
 
-	m.currOutputIndex++
-	m.isCurrentlyMismatched.Set(0)
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query %s chain id: %w", layer, err)
+	}
+	if !chainID.IsUint64() || chainID.Uint64() != expected {
+		return fmt.Errorf("%s node is connected to chain %s, expected %d", layer, chainID, expected)
+	}
+	return nil
 }
 
-func (m *Monitor) Close(_ context.Context) error {
-	m.l1Client.Close()
-	m.l2Client.Close()
+// checkContractCode fails if there is no code at the address, as is the case for a mistyped
+// address or an l1 node connected to the wrong chain. Calls to the contract would otherwise
+// fail with a confusing abi error.
+func checkContractCode(ctx context.Context, client bind.ContractCaller, name string, address common.Address) error {
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to query code of the %s at %s: %w", name, address, err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no contract deployed at the %s address %s, check the address and the l1 node's chain", name, address)
+	}
 	return nil
 }
 
-func (m *Monitor) findFirstUnfinalizedOutputIndex(ctx context.Context, finalizationWindow uint64) (uint64, error) {
-	m.log.Info("searching for first unfinalized output")
+// probeL2Methods calls the l2 methods outputs are reconstructed with once, failing fast on a
+// node not serving one of them, such as providers without eth_getProof, rather than failing
+// every loop
+func probeL2Methods(ctx context.Context, client *ethclient.Client, layer string, messagePasser common.Address, proofByNumber bool) error {
+	probeErr := func(method string, err error) error {
+		if isMethodNotFound(err) {
+			return fmt.Errorf("%s node does not support %s, which is required to reconstruct output roots: %w", layer, method, err)
+		}
+		return fmt.Errorf("failed to probe %s on the %s node: %w", method, layer, err)
+	}
+
+	if _, err := client.BlockNumber(ctx); err != nil {
+		return probeErr("eth_blockNumber", err)
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return probeErr("eth_getBlockByNumber", err)
+	}
+
+	proofBlock := rpc.BlockNumberOrHashWithHash(header.Hash(), true)
+	if proofByNumber {
+		proofBlock = rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(header.Number.Int64()))
+	}
+	var proof storageProof
+	if err := client.Client().CallContext(ctx, &proof, "eth_getProof", messagePasser, nil, proofBlock); err != nil {
+		return probeErr("eth_getProof", err)
+	}
+	return nil
+}
+
+// outputSource is the contract outputs are posted to, matching the L2OutputOracle
+type outputSource interface {
+	NextOutputIndex(opts *bind.CallOpts) (*big.Int, error)
+	GetL2Output(opts *bind.CallOpts, index *big.Int) (bindings.TypesOutputProposal, error)
+	FinalizationPeriodSeconds(opts *bind.CallOpts) (*big.Int, error)
+
+	// Proposer returns the address that proposed the output at the index
+	Proposer(opts *bind.CallOpts, index *big.Int) (common.Address, error)
+}
+
+// outputProposedTopic is the topic of the L2OutputOracle's OutputProposed logs
+var outputProposedTopic = crypto.Keccak256Hash([]byte("OutputProposed(bytes32,uint256,uint256,uint256)"))
+
+const (
+	// l1SlotSeconds is the minimum time between l1 blocks, one per slot at most
+	l1SlotSeconds = 12
+	// proposalRangeBlocks is the width of the l1 block range searched for an OutputProposed log,
+	// narrowed down in at most proposalRangeSteps header queries
+	proposalRangeBlocks = 1000
+	proposalRangeSteps  = 8
+)
+
+// proposalLogReader reads the logs and transactions proposing outputs
+type proposalLogReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error)
+}
+
+// l2OutputOracleOutputs are the outputs posted to the L2OutputOracle
+type l2OutputOracleOutputs struct {
+	*bindings.L2OutputOracleCaller
+	address common.Address
+	logs    proposalLogReader
+}
+
+// Proposer returns the sender of the transaction that proposed the output at the index, found
+// from its OutputProposed log. The oracle's proposer may have changed since, such as on upgrades.
+// The log is searched for in the l1 blocks around the output's timestamp, the time it was proposed.
+func (o *l2OutputOracleOutputs) Proposer(opts *bind.CallOpts, index *big.Int) (common.Address, error) {
+	output, err := o.GetL2Output(opts, index)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to query output: %w", err)
+	}
+	return o.proposerAt(opts.Context, index, output.Timestamp.Uint64())
+}
+
+// proposerAt returns the sender of the transaction that proposed the output at the index, at
+// the timestamp
+func (o *l2OutputOracleOutputs) proposerAt(ctx context.Context, index *big.Int, timestamp uint64) (common.Address, error) {
+	from, to, err := proposalBlockRange(ctx, o.logs, timestamp)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	logs, err := o.logs.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{o.address},
+		Topics:    [][]common.Hash{{outputProposedTopic}, nil, {common.BigToHash(index)}},
+	})
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to query OutputProposed logs: %w", err)
+	}
+	if len(logs) == 0 {
+		return common.Address{}, fmt.Errorf("no OutputProposed log of output %d: %w", index, ethereum.NotFound)
+	}
+
+	// an index is proposed again after its output is deleted, the last log being the current output
+	proposal := logs[len(logs)-1]
+	tx, _, err := o.logs.TransactionByHash(ctx, proposal.TxHash)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to query proposal transaction %s: %w", proposal.TxHash, err)
+	}
+	return o.logs.TransactionSender(ctx, tx, proposal.BlockHash, proposal.TxIndex)
+}
+
+// proposalBlockRange returns a range of l1 blocks including the block of the timestamp. As l1
+// blocks are at least a slot apart, the blocks between a block and the timestamp number at most
+// the slots between them, bounding the block from below with a later block and from above with
+// an earlier one. The range is narrowed down alternating both bounds, each one leaving only
+// the slots missed in between.
+func proposalBlockRange(ctx context.Context, headers proposalLogReader, timestamp uint64) (uint64, uint64, error) {
+	header, err := headers.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query latest l1 header: %w", err)
+	}
+	if header.Time <= timestamp {
+		return header.Number.Uint64(), header.Number.Uint64(), nil
+	}
+
+	for step := 1; ; step++ {
+		var lower uint64
+		if slots := (header.Time - timestamp) / l1SlotSeconds; slots < header.Number.Uint64() {
+			lower = header.Number.Uint64() - slots
+		}
+		if header, err = headers.HeaderByNumber(ctx, new(big.Int).SetUint64(lower)); err != nil {
+			return 0, 0, fmt.Errorf("failed to query l1 header %d: %w", lower, err)
+		}
+		if header.Time > timestamp {
+			return 0, 0, fmt.Errorf("l1 block %d is past the proposal time %d, l1 blocks being less than a slot apart", lower, timestamp)
+		}
+
+		upper := lower + (timestamp-header.Time)/l1SlotSeconds
+		if upper-lower <= proposalRangeBlocks || step >= proposalRangeSteps {
+			return lower, upper, nil
+		}
+		if header, err = headers.HeaderByNumber(ctx, new(big.Int).SetUint64(upper)); err != nil {
+			return 0, 0, fmt.Errorf("failed to query l1 header %d: %w", upper, err)
+		}
+		if header.Time < timestamp {
+			return 0, 0, fmt.Errorf("l1 block %d is before the proposal time %d, l1 blocks being less than a slot apart", upper, timestamp)
+		}
+	}
+}
+
+// bindOutputs binds to the contract outputs are posted to. Chains using fault proofs
+// have no L2OutputOracle, in which case the games created by the portal's
+// DisputeGameFactory are validated instead. A configured oracle address is bound
+// directly, without querying the portal.
+func (m *Monitor) bindOutputs(ctx context.Context, portalAddress, l2OOAddress common.Address) error {
 	callOpts := &bind.CallOpts{Context: ctx}
 
-	latestBlock, err := m.l2Client.BlockByNumber(ctx, nil)
+	if l2OOAddress != (common.Address{}) {
+		m.log.Info("configured L2OutputOracle from its address, skipping the OptimismPortal", "address", l2OOAddress.String())
+	} else {
+		if err := checkContractCode(ctx, m.l1Client, "OptimismPortal", portalAddress); err != nil {
+			return err
+		}
+		optimismPortal, err := bindings.NewOptimismPortalCaller(portalAddress, m.l1Client)
+		if err != nil {
+			return fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
+		}
+
+		l2OOAddress, err = optimismPortal.L2ORACLE(callOpts)
+		if err != nil {
+			m.log.Info("no L2OutputOracle found, checking for a DisputeGameFactory", "err", err)
+			if dgfErr := m.bindDisputeGames(ctx, portalAddress); dgfErr != nil {
+				return errors.Join(fmt.Errorf("failed to query L2OO address: %w", err), dgfErr)
+			}
+			return nil
+		}
+		m.log.Info("configured L2OutputOracle from the OptimismPortal", "address", l2OOAddress.String(), "portal", portalAddress.String())
+	}
+
+	if err := checkContractCode(ctx, m.l1Client, "L2OutputOracle", l2OOAddress); err != nil {
+		return err
+	}
+	l2OO, err := bindings.NewL2OutputOracleCaller(l2OOAddress, m.l1Client)
+	if err != nil {
+		return fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+	}
+	startingBlockNumber, err := l2OO.StartingBlockNumber(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query for starting block number: %w", err)
+	}
+	submissionInterval, err := l2OO.SUBMISSIONINTERVAL(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query for submission interval: %w", err)
+	}
+	startingTimestamp, err := l2OO.StartingTimestamp(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query for starting timestamp: %w", err)
+	}
+	l2BlockTime, err := l2OO.L2BLOCKTIME(callOpts)
+	if err != nil {
+		return fmt.Errorf("failed to query for l2 block time: %w", err)
+	}
+	m.log.Info("configured output schedule", "starting_block_number", startingBlockNumber, "submission_interval", submissionInterval, "l2_block_time", l2BlockTime)
+
+	m.outputs = &l2OutputOracleOutputs{L2OutputOracleCaller: l2OO, address: l2OOAddress, logs: m.l1Client}
+	m.outputsContract, m.outputsAddress = "L2OutputOracle", l2OOAddress
+	m.startingBlockNumber = startingBlockNumber.Uint64()
+	m.submissionInterval = submissionInterval.Uint64()
+	m.startingTimestamp = startingTimestamp.Uint64()
+	m.l2BlockTime = l2BlockTime.Uint64()
+	m.submissionIntervalGauge.Set(float64(m.submissionInterval))
+	m.l2BlockTimeGauge.Set(float64(m.l2BlockTime))
+	return nil
+}
+
+// outputCheck is the result of reconstructing a posted output from L2
+type outputCheck struct {
+	index  uint64
+	output bindings.TypesOutputProposal
+	block  *types.Block
+
+	// reconstructed output root, unset if the verifier does not reconstruct it
+	outputRoot eth.Bytes32
+	// result of verifying the posted output against l2
+	valid bool
+
+	proposer common.Address
+
+	// output root provided by the rollup node, if cross-checked
+	rollupOutputRoot *eth.Bytes32
+}
+
+// mismatched returns true if either the reconstructed or rollup node's output
+// root differs from the posted one
+func (c *outputCheck) mismatched() bool {
+	if c.rollupOutputRoot != nil && *c.rollupOutputRoot != eth.Bytes32(c.output.OutputRoot) {
+		return true
+	}
+	return !c.valid
+}
+
+var (
+	errL2NodeBehind       = errors.New("l2 node is behind")
+	errL2NodeStuck        = errors.New("l2 node is stuck behind the output")
+	errReconstructionRace = errors.New("l2 block changed during reconstruction")
+	errEmptyProof         = errors.New("empty storage root in proof response")
+	errZeroStateRoot      = errors.New("zero state root in l2 block")
+	errZeroOutputData     = errors.New("zero output data in output response")
+	errOutputReverted     = errors.New("output query reverted")
+	errL2NodeDisagreement = errors.New("l2 nodes disagree")
+	errNonCanonicalBlock  = errors.New("l2 block is not canonical")
+	errOutputInvalidated  = errors.New("output invalidated on l1")
+)
+
+func (m *Monitor) Run(ctx context.Context) {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	// paused on purpose, still reported as live so the monitor is not restarted
+	if m.circuitOpen.Load() {
+		m.log.Warn("validation paused by the circuit breaker, resume it or restart the monitor", "consecutive_mismatches", m.consecutiveMismatches)
+		m.lastSuccessfulTick.Store(m.clock.Now().UnixNano())
+		m.publishState()
+		return
+	}
+
+	// waiting on a lagging l2 node or a reorg is tracked separately and does not fail the tick
+	err := m.tick(ctx)
+	if errors.Is(err, errOutputReverted) && m.correctOutOfRangeIndex(ctx) {
+		err = nil
+	}
+	if err == nil || errors.Is(err, errL2NodeBehind) || errors.Is(err, errReconstructionRace) {
+		m.lastSuccessfulTick.Store(m.clock.Now().UnixNano())
+	} else if ctx.Err() == nil {
+		m.monitorErrorsTotal.WithLabelValues(classifyError(err)).Inc()
+	}
+	m.lastTick.Store(&tickResult{time: m.clock.Now(), err: err})
+	m.publishState()
+}
+
+// tickResult is the outcome of a tick
+type tickResult struct {
+	time time.Time
+	err  error
+}
+
+// MonitorStatus is the progress of a monitor, for embedders supervising it
+type MonitorStatus struct {
+	CurrOutputIndex uint64
+	// next output index last seen on l1, zero until first queried
+	NextKnownIndex uint64
+	IsMismatched   bool
+
+	// time the last tick completed and its error, including waits on a lagging l2 node
+	// which do not fail the tick. Zero until the first tick completed
+	LastTickTime time.Time
+	LastError    error
+
+	LastSuccessfulTick time.Time
+}
+
+// Status returns the progress of the monitor as of its last tick, safe to call concurrently with ticks
+func (m *Monitor) Status() MonitorStatus {
+	status := MonitorStatus{LastSuccessfulTick: m.LastSuccessfulTick()}
+	if state := m.state.Load(); state != nil {
+		status.CurrOutputIndex = state.CurrOutputIndex
+		status.NextKnownIndex = state.NextOutputIndex
+		status.IsMismatched = state.CurrentlyMismatched
+	}
+	if tick := m.lastTick.Load(); tick != nil {
+		status.LastTickTime = tick.time
+		status.LastError = tick.err
+	}
+	return status
+}
+
+// MonitorInfo is the resolved configuration and progress of a monitor
+type MonitorInfo struct {
+	OutputsContract     string         `json:"outputs_contract"`
+	OutputsAddress      common.Address `json:"outputs_address"`
+	FaultProofWindow    uint64         `json:"fault_proof_window"`
+	LoopIntervalMs      uint64         `json:"loop_interval_ms"`
+	CurrOutputIndex     uint64         `json:"curr_output_index"`
+	NextOutputIndex     uint64         `json:"next_output_index"`
+	CurrentlyMismatched bool           `json:"currently_mismatched"`
+	CircuitBreakerOpen  bool           `json:"circuit_breaker_open"`
+}
+
+// publishState snapshots the state of the monitor for concurrent readers
+func (m *Monitor) publishState() {
+	m.state.Store(&MonitorInfo{
+		OutputsContract:     m.outputsContract,
+		OutputsAddress:      m.outputsAddress,
+		FaultProofWindow:    m.faultProofWindow.Load(),
+		LoopIntervalMs:      m.loopIntervalMs.Load(),
+		CurrOutputIndex:     m.currOutputIndex,
+		NextOutputIndex:     m.nextKnownIndex,
+		CurrentlyMismatched: len(m.mismatchedIndexes) > 0,
+		CircuitBreakerOpen:  m.circuitOpen.Load(),
+	})
+}
+
+func (m *Monitor) info() any {
+	state := m.state.Load()
+	if state == nil {
+		return state
+	}
+	// the interval may have been reloaded since the last tick
+	info := *state
+	info.LoopIntervalMs = m.loopIntervalMs.Load()
+	return &info
+}
+
+// SetLoopInterval updates the loop interval reported on /info and the max age of the last
+// successful tick before /healthz fails, as the interval is reloaded without a restart
+func (m *Monitor) SetLoopInterval(interval time.Duration) {
+	m.loopIntervalMs.Store(uint64(interval.Milliseconds()))
+	if m.health != nil {
+		m.health.setLoopInterval(interval)
+	}
+}
+
+// tick validates the next posted outputs, returning an error if any
+// could not be checked
+func (m *Monitor) tick(ctx context.Context) error {
+	m.activeL1Endpoint.Set(float64(m.l1Client.activeIndex()))
+	m.ticks++
+	if m.windowRefreshTicks > 0 && m.ticks%m.windowRefreshTicks == 0 {
+		m.refreshFaultProofWindow(ctx)
+	}
+
+	// Check for available outputs to validate
+
+	confirmedBlock, err := m.confirmedL1Block(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			// shutting down
+			return err
+		}
+		m.log.Error("failed to query l1 block number", "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l1", "blockNumber").Inc()
+		return err
+	}
+	nextOutputIndex, err := withRetries(ctx, m, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx, BlockNumber: confirmedBlock})
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			// shutting down
+			return err
+		}
+		m.log.Error("failed to query next output index", "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l1", "nextOutputIndex").Inc()
+		return err
+	}
+	m.nextKnownIndex = nextOutputIndex.Uint64()
+	defer func() { m.trackCaughtUp(nextOutputIndex.Uint64()) }()
+	m.trackProposer(nextOutputIndex.Uint64())
+	m.checkL2TimeSkew(ctx)
+	m.trackUnfinalizedOutputs(ctx, nextOutputIndex.Uint64())
+	m.confirmGraceSuppressed(ctx)
+
+	// Rewind on l1 reorgs removing outputs. The lower index must be seen on two consecutive
+	// ticks to guard against a single response from an out-of-sync l1 node
+
+	if nextOutputIndex.Uint64() < m.currOutputIndex {
+		if !m.suspectedReorg {
+			m.log.Warn("next output index decreased, waiting for confirmation", "index", m.currOutputIndex, "next_index", nextOutputIndex)
+			m.suspectedReorg = true
+			return nil
+		}
+
+		m.log.Warn("l1 reorg detected, rewinding output index", "old_index", m.currOutputIndex, "new_index", nextOutputIndex)
+		m.l1Reorgs.Inc()
+		m.currOutputIndex = nextOutputIndex.Uint64()
+		for index := range m.mismatchedIndexes {
+			if index >= m.currOutputIndex {
+				m.clearMismatch(index)
+			}
+		}
+		if len(m.mismatchedIndexes) == 0 {
+			m.isCurrentlyMismatched.Set(0)
+		}
+		m.persistCheckpoint()
+	}
+	m.suspectedReorg = false
+
+	if m.continueOnMismatch && len(m.mismatchedIndexes) > 0 {
+		m.recheckMismatch(ctx)
+	}
+
+	if m.currOutputIndex >= nextOutputIndex.Uint64() {
+		m.logWaiting(nextOutputIndex.Uint64())
+		m.outputIndexLag.Set(0)
+		m.resetStuck()
+		return nil
+	}
+	m.lastWaitLog = time.Time{}
+	defer m.trackProgress(m.currOutputIndex)
+
+	lag := nextOutputIndex.Uint64() - m.currOutputIndex
+	m.outputIndexLag.Set(float64(lag))
+	m.highestOutputIndex.WithLabelValues("known").Set(float64(nextOutputIndex.Int64()))
+
+	if m.catchUpThreshold > 0 && lag > m.catchUpThreshold {
+		return m.catchUp(ctx, nextOutputIndex.Uint64())
+	}
+
+	// Sequentially check up to the max outputs per tick, stopping early once
+	// caught up or halted on a mismatch
+
+	for checked := uint64(0); checked < m.maxOutputsPerTick && m.currOutputIndex < nextOutputIndex.Uint64(); checked++ {
+		if m.skipUnsampled(nextOutputIndex.Uint64()) {
+			break
+		}
+		m.log.Info("checking output", "index", m.currOutputIndex)
+		check, err := m.checkOutput(ctx, m.currOutputIndex)
+		if err == nil {
+			check, err = m.confirmMismatch(ctx, check)
+		}
+		if m.skipStuckOutput(err) || m.skipInvalidatedOutput(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !m.applyCheck(ctx, check) {
+			break
+		}
+		m.logCatchUpProgress(nextOutputIndex.Uint64())
+		m.outputIndexLag.Set(float64(nextOutputIndex.Uint64() - m.currOutputIndex))
+	}
+	return nil
+}
+
+// trackCaughtUp records whether the monitor checked every posted output as of the end of the
+// tick, logging the first time it caught up after startup
+func (m *Monitor) trackCaughtUp(nextOutputIndex uint64) {
+	caughtUp := m.currOutputIndex >= nextOutputIndex
+	switch {
+	case caughtUp && !m.everCaughtUp:
+		m.log.Info("caught up with the posted outputs, tracking new proposals", "index", m.currOutputIndex)
+		m.everCaughtUp = true
+	case !caughtUp && m.caughtUp:
+		m.log.Info("fell behind the posted outputs", "index", m.currOutputIndex, "next_index", nextOutputIndex)
+	}
+	m.caughtUp = caughtUp
+	if caughtUp {
+		m.progressIndex, m.progressTime = m.currOutputIndex, m.clock.Now()
+		m.caughtUpGauge.Set(1)
+	} else {
+		m.caughtUpGauge.Set(0)
+	}
+}
+
+// logCatchUpProgress logs the progress of a catch-up every progress interval of indexes, with
+// the rate since the last log, showing a long catch-up is making headway
+func (m *Monitor) logCatchUpProgress(nextOutputIndex uint64) {
+	if m.progressInterval == 0 || m.currOutputIndex >= nextOutputIndex || m.currOutputIndex < m.progressIndex+m.progressInterval {
+		return
+	}
+
+	now := m.clock.Now()
+	ctx := []any{"index", m.currOutputIndex, "next_index", nextOutputIndex, "remaining", nextOutputIndex - m.currOutputIndex}
+	if elapsed := now.Sub(m.progressTime); elapsed > 0 {
+		ctx = append(ctx, "outputs_per_sec", float64(m.currOutputIndex-m.progressIndex)/elapsed.Seconds())
+	}
+	m.log.Info("catching up on outputs", ctx...)
+	m.progressIndex, m.progressTime = m.currOutputIndex, now
+}
+
+// skipUnsampled advances past the outputs not sampled when sampling every nth output, up to
+// the next output index. Returns true if caught up on the posted outputs.
+func (m *Monitor) skipUnsampled(nextOutputIndex uint64) bool {
+	if m.sampleEveryN <= 1 {
+		return false
+	}
+
+	skipped := false
+	for m.currOutputIndex%m.sampleEveryN != 0 && m.currOutputIndex < nextOutputIndex {
+		m.log.Debug("skipping unsampled output", "index", m.currOutputIndex)
+		m.unsampledOutputs.Inc()
+		m.currOutputIndex++
+		skipped = true
+	}
+	if skipped {
+		m.persistCheckpoint()
+	}
+	return m.currOutputIndex >= nextOutputIndex
+}
+
+// skipStuckOutput advances past the current output if the l2 node is stuck behind it and
+// configured to skip such outputs. Returns true if skipped.
+func (m *Monitor) skipStuckOutput(err error) bool {
+	if m.l2BehindBehavior != L2BehindBehaviorSkip || !errors.Is(err, errL2NodeStuck) {
+		return false
+	}
+	m.log.Warn("skipping output the l2 node is stuck behind, it is not validated", "index", m.currOutputIndex)
+	m.l2BehindSkippedOutputs.Inc()
+	m.currOutputIndex++
+	m.persistCheckpoint()
+	return true
+}
+
+// skipInvalidatedOutput advances past the current output if it was invalidated on l1, such as
+// a dispute game resolved in favor of the challenger, forgetting it as mismatched as it can no
+// longer be finalized. Returns true if skipped.
+func (m *Monitor) skipInvalidatedOutput(err error) bool {
+	if !errors.Is(err, errOutputInvalidated) {
+		return false
+	}
+	m.invalidatedOutputs.Inc()
+	m.forgetInvalidatedMismatch(m.currOutputIndex)
+	m.currOutputIndex++
+	m.persistCheckpoint()
+	return true
+}
+
+// forgetInvalidatedMismatch clears the index as mismatched once its output was invalidated
+func (m *Monitor) forgetInvalidatedMismatch(index uint64) {
+	if _, ok := m.mismatchedIndexes[index]; !ok {
+		return
+	}
+	m.clearMismatch(index)
+	m.log.Warn("mismatched output invalidated on l1", "index", index, "remaining_mismatches", len(m.mismatchedIndexes))
+	if len(m.mismatchedIndexes) == 0 {
+		m.isCurrentlyMismatched.Set(0)
+		m.lastMismatch.Reset()
+	}
+}
+
+// refreshFaultProofWindow re-reads the finalization period, which may have changed on
+// an upgrade of the contract. Failures keep the last known window.
+func (m *Monitor) refreshFaultProofWindow(ctx context.Context) {
+	window, err := withRetries(ctx, m, "l1", "finalizationPeriodSeconds", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.FinalizationPeriodSeconds(&bind.CallOpts{Context: ctx})
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		m.log.Error("failed to refresh finalization window", "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l1", "finalizationPeriodSeconds").Inc()
+		return
+	}
+
+	if prev := m.faultProofWindow.Swap(window.Uint64()); prev != window.Uint64() {
+		m.log.Warn("finalization window changed", "old_window", prev, "new_window", window)
+	}
+	m.faultProofWindowSeconds.Set(float64(window.Uint64()))
+}
+
+// confirmMismatch re-checks a newly mismatched output up to the configured number of
+// confirmations, guarding against transient inconsistencies of the l2 node. The latest
+// check is returned, matching if the mismatch was transient.
+func (m *Monitor) confirmMismatch(ctx context.Context, check *outputCheck) (*outputCheck, error) {
+	if !check.mismatched() {
+		return check, nil
+	}
+	if _, ok := m.mismatchedIndexes[check.index]; ok {
+		// already confirmed
+		return check, nil
+	}
+	if _, ok := m.ignoredMismatchIndexes[check.index]; ok {
+		return check, nil
+	}
+
+	for confirmation := uint64(1); confirmation <= m.mismatchConfirmations; confirmation++ {
+		m.log.Warn("re-verifying suspected mismatch", "index", check.index, "confirmation", confirmation, "confirmations", m.mismatchConfirmations)
+		recheck, err := m.checkOutput(ctx, check.index)
+		if err != nil {
+			return nil, err
+		}
+		if !recheck.mismatched() {
+			m.log.Warn("suspected mismatch was transient", "index", check.index, "confirmation", confirmation)
+			return recheck, nil
+		}
+		check = recheck
+	}
+	return check, nil
+}
+
+// applyCheck records the result of checking the current output, advancing to the next
+// index unless halting on a mismatch. Returns true if the monitor advanced and may check
+// the next index, false if halted or paused by the circuit breaker.
+func (m *Monitor) applyCheck(ctx context.Context, check *outputCheck) bool {
+	m.validatedOutputAgeSeconds.Set(m.clock.Since(time.Unix(int64(check.block.Time()), 0)).Seconds())
+	if m.auditLog != nil {
+		if err := m.auditLog.record(check); err != nil {
+			m.log.Error("failed to record output to audit log", "index", check.index, "err", err)
+		}
+	}
+	m.secondsUntilFinalization.Set(m.finalizationTime(check).Sub(m.clock.Now()).Seconds())
+	m.outputFinalizationDeadlineUnix.Reset()
+	m.outputFinalizationDeadlineUnix.WithLabelValues(strconv.FormatUint(check.index, 10)).Set(float64(m.finalizationTime(check).Unix()))
+	if _, ok := m.ignoredMismatchIndexes[check.index]; ok && check.mismatched() {
+		m.log.Info("ignoring known mismatch", "index", check.index, "l2_block_number", check.output.L2BlockNumber, "expected_output_root", check.outputRoot.String(), "actual_output_root", common.Hash(check.output.OutputRoot).String())
+		m.ignoredMismatches.Inc()
+		m.highestOutputIndex.WithLabelValues("checked").Set(float64(check.index))
+		m.currOutputIndex++
+		m.persistCheckpoint()
+		return true
+	}
+	if check.mismatched() {
+		m.logMismatch(check)
+		if _, ok := m.mismatchedIndexes[check.index]; !ok {
+			m.mismatchedIndexes[check.index] = struct{}{}
+			m.outputMismatchState.WithLabelValues(strconv.FormatUint(check.index, 10)).Set(1)
+			m.mismatchedOutputIndexes.Inc()
+			m.countProposal(check)
+			m.setLastMismatch(check)
+			m.countConsecutiveMismatch()
+			m.observeValidationDelay(check)
+		}
+		// repeats while halted on the mismatch are deduplicated
+		m.notifyMismatch(check)
+		m.isCurrentlyMismatched.Set(1)
+		if !m.continueOnMismatch {
+			return false
+		}
+
+		m.highestOutputIndex.WithLabelValues("checked").Set(float64(check.index))
+		m.currOutputIndex++
+		return !m.circuitOpen.Load()
+	}
+
+	// Continue
+
+	m.logValidated(check)
+	m.consecutiveMismatches = 0
+	m.highestOutputIndex.WithLabelValues("checked").Set(float64(check.index))
+	m.outputsValidatedTotal.Inc()
+	if _, ok := m.mismatchedIndexes[check.index]; ok {
+		// the proposal was counted on first sight
+		m.recoverMismatch(check)
+	} else {
+		m.countProposal(check)
+		m.observeValidationDelay(check)
+	}
+
+	m.currOutputIndex++
+	if len(m.mismatchedIndexes) == 0 {
+		m.isCurrentlyMismatched.Set(0)
+		m.lastMismatch.Reset()
+	}
+
+	m.persistCheckpoint()
+	return true
+}
+
+// confirmedL1Block returns the l1 block the next output index is read at, the configured
+// number of confirmation blocks behind the head, or nil to read it at the head
+func (m *Monitor) confirmedL1Block(ctx context.Context) (*big.Int, error) {
+	if m.l1ConfirmationBlocks == 0 {
+		return nil, nil
+	}
+
+	head, err := withRetries(ctx, m, "l1", "blockNumber", func(ctx context.Context) (uint64, error) {
+		return m.l1Client.activeClient().BlockNumber(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if head < m.l1ConfirmationBlocks {
+		return new(big.Int), nil
+	}
+	return new(big.Int).SetUint64(head - m.l1ConfirmationBlocks), nil
+}
+
+// observeValidationDelay records the time between the output being posted and checked, once
+// per output, as the detection latency of a fault relative to the finalization period
+func (m *Monitor) observeValidationDelay(check *outputCheck) {
+	if check.output.Timestamp == nil {
+		return
+	}
+	m.outputValidationDelay.Observe(m.clock.Since(time.Unix(check.output.Timestamp.Int64(), 0)).Seconds())
+}
+
+// countConsecutiveMismatch opens the circuit breaker once the configured number of distinct
+// indexes mismatched in a row, as is likely of a misconfiguration rather than faulty outputs,
+// pausing validation instead of alerting on every subsequent index
+func (m *Monitor) countConsecutiveMismatch() {
+	m.consecutiveMismatches++
+	if m.maxConsecutiveMismatches == 0 || m.consecutiveMismatches < m.maxConsecutiveMismatches {
+		return
+	}
+
+	m.log.Error("too many consecutive mismatches, pausing validation. check the configuration of the monitor and resume it", "consecutive_mismatches", m.consecutiveMismatches, "index", m.currOutputIndex)
+	m.circuitOpen.Store(true)
+	m.circuitBreakerOpen.Set(1)
+}
+
+// resumeValidation closes the circuit breaker, resuming validation from the next tick
+func (m *Monitor) resumeValidation() {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	if !m.circuitOpen.Load() {
+		return
+	}
+	m.log.Info("resuming validation", "index", m.currOutputIndex)
+	m.consecutiveMismatches = 0
+	m.circuitOpen.Store(false)
+	m.circuitBreakerOpen.Set(0)
+	m.publishState()
+}
+
+// Reset rewinds or fast-forwards validation to the index at runtime, such as to re-validate a
+// range during an investigation, clearing the mismatch state and closing the circuit breaker.
+// It waits for an in-flight tick to complete, and the index must not be past the next output
+// index posted on l1.
+func (m *Monitor) Reset(ctx context.Context, index uint64) error {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	nextOutputIndex, err := withRetries(ctx, m, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query next output index: %w", err)
+	}
+	if index > nextOutputIndex.Uint64() {
+		return fmt.Errorf("index %d is past the next output index %d", index, nextOutputIndex)
+	}
+
+	m.log.Warn("resetting validation", "old_index", m.currOutputIndex, "new_index", index, "cleared_mismatches", len(m.mismatchedIndexes))
+	m.currOutputIndex = index
+	for mismatched := range m.mismatchedIndexes {
+		m.clearMismatch(mismatched)
+	}
+	m.isCurrentlyMismatched.Set(0)
+	m.lastMismatch.Reset()
+	m.consecutiveMismatches = 0
+	m.circuitOpen.Store(false)
+	m.circuitBreakerOpen.Set(0)
+	m.suspectedReorg = false
+	m.resetStuck()
+	m.persistCheckpoint()
+	m.publishState()
+	return nil
+}
+
+// resetValidation resets the monitor to the index, for the admin endpoint. The monitor is
+// unnamed, so no chain may be given.
+func (m *Monitor) resetValidation(ctx context.Context, chain string, index uint64) error {
+	if chain != "" {
+		return fmt.Errorf("unknown chain %q", chain)
+	}
+	return m.Reset(ctx, index)
+}
+
+// recoverMismatch clears a previously mismatched index found to match its reconstructed
+// output root, such as after the output was corrected
+func (m *Monitor) recoverMismatch(check *outputCheck) {
+	m.clearMismatch(check.index)
+	m.mismatchRecoveries.Inc()
+	m.log.Warn("previously mismatched output recovered", "index", check.index, "output_root", check.outputRoot.String(), "remaining_mismatches", len(m.mismatchedIndexes))
+	if len(m.mismatchedIndexes) == 0 {
+		m.isCurrentlyMismatched.Set(0)
+		m.lastMismatch.Reset()
+	}
+}
+
+// clearMismatch forgets the index as mismatched, alerting of it again should it mismatch again
+func (m *Monitor) clearMismatch(index uint64) {
+	delete(m.mismatchedIndexes, index)
+	m.outputMismatchState.DeleteLabelValues(strconv.FormatUint(index, 10))
+	if m.alertDedup != nil {
+		m.alertDedup.clear(index)
+	}
+}
+
+// restoreMismatches re-checks the outputs left mismatched behind the checkpoint, restoring the
+// mismatch state of those still mismatched and alerting of them again. Outputs failing to be
+// checked are kept as mismatched, to be re-checked along with the others on later ticks.
+func (m *Monitor) restoreMismatches(ctx context.Context, indexes []uint64) {
+	for _, index := range indexes {
+		if index >= m.currOutputIndex {
+			continue
+		}
+
+		m.log.Info("re-checking output mismatched before the restart", "index", index)
+		check, err := m.checkOutput(ctx, index)
+		switch {
+		case errors.Is(err, errOutputInvalidated):
+			continue
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			m.log.Warn("failed to re-check output mismatched before the restart, keeping it mismatched", "index", index, "err", err)
+		case check.mismatched():
+			m.logMismatch(check)
+			m.setLastMismatch(check)
+			m.notifyMismatch(check)
+		default:
+			m.log.Info("output mismatched before the restart now matches", "index", index)
+			continue
+		}
+		m.mismatchedIndexes[index] = struct{}{}
+		m.outputMismatchState.WithLabelValues(strconv.FormatUint(index, 10)).Set(1)
+		m.isCurrentlyMismatched.Set(1)
+	}
+}
+
+// recheckMismatch re-validates one of the mismatched indexes skipped past when continuing
+// on mismatches, cycling through them on every call, clearing it if it now matches
+func (m *Monitor) recheckMismatch(ctx context.Context) {
+	indexes := make([]uint64, 0, len(m.mismatchedIndexes))
+	for index := range m.mismatchedIndexes {
+		if index < m.currOutputIndex {
+			indexes = append(indexes, index)
+		}
+	}
+	if len(indexes) == 0 {
+		return
+	}
+	slices.Sort(indexes)
+
+	index := indexes[0]
+	if i, _ := slices.BinarySearch(indexes, m.recheckCursor); i < len(indexes) {
+		index = indexes[i]
+	}
+	m.recheckCursor = index + 1
+
+	m.log.Info("re-checking mismatched output", "index", index)
+	check, err := m.checkOutput(ctx, index)
+	if errors.Is(err, errOutputInvalidated) {
+		m.forgetInvalidatedMismatch(index)
+		m.persistCheckpoint()
+		return
+	}
+	if err != nil || check.mismatched() {
+		return
+	}
+	m.recoverMismatch(check)
+	m.persistCheckpoint()
+}
+
+// correctOutOfRangeIndex clamps the current output index back to the next output index
+// if it went out of range, such as after outputs were deleted. Returns true if corrected
+func (m *Monitor) correctOutOfRangeIndex(ctx context.Context) bool {
+	nextOutputIndex, err := withRetries(ctx, m, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx})
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to query latest block: %w", err)
+		if ctx.Err() == nil {
+			m.log.Error("failed to query next output index", "err", err)
+			m.nodeConnectionFailures.WithLabelValues("l1", "nextOutputIndex").Inc()
+		}
+		return false
+	}
+	if m.currOutputIndex <= nextOutputIndex.Uint64() {
+		return false
+	}
+
+	m.log.Warn("output index out of range, correcting", "old_index", m.currOutputIndex, "new_index", nextOutputIndex)
+	m.indexOutOfRangeCorrections.Inc()
+	m.currOutputIndex = nextOutputIndex.Uint64()
+	for index := range m.mismatchedIndexes {
+		if index >= m.currOutputIndex {
+			m.clearMismatch(index)
+		}
+	}
+	if len(m.mismatchedIndexes) == 0 {
+		m.isCurrentlyMismatched.Set(0)
+		m.lastMismatch.Reset()
+	}
+	m.persistCheckpoint()
+	return true
+}
+
+// trackProgress counts the consecutive ticks with outputs available where the current index
+// did not advance, escalating past the max stuck ticks. Halting on a mismatch is not stuck
+func (m *Monitor) trackProgress(start uint64) {
+	_, halted := m.mismatchedIndexes[m.currOutputIndex]
+	if m.currOutputIndex != start || (halted && !m.continueOnMismatch) {
+		m.resetStuck()
+		return
+	}
+
+	m.stuckTicks++
+	m.stuckTicksGauge.Set(float64(m.stuckTicks))
+	if m.maxStuckTicks > 0 && m.stuckTicks > m.maxStuckTicks {
+		m.log.Error("monitor stuck, output index not advancing despite available outputs", "index", m.currOutputIndex, "ticks", m.stuckTicks)
+		m.monitorStuck.Set(1)
+	}
+}
+
+func (m *Monitor) resetStuck() {
+	m.stuckTicks = 0
+	m.stuckTicksGauge.Set(0)
+	m.monitorStuck.Set(0)
+}
+
+// checkL2TimeSkew fetches the latest l2 header to track the time skew. Failures are logged
+// without failing the tick, as the skew only informs finalization estimates.
+func (m *Monitor) checkL2TimeSkew(ctx context.Context) {
+	header, err := withRetries(ctx, m, "l2", "headerByNumber", func(ctx context.Context) (*types.Header, error) {
+		return m.l2Client.HeaderByNumber(ctx, nil)
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			m.log.Warn("failed to query latest l2 header for the time skew", "err", err)
+		}
+		return
+	}
+	m.trackL2TimeSkew(header)
+}
+
+// trackUnfinalizedOutputs counts the posted outputs still within the finalization window,
+// which could still be challenged. The first unfinalized index is searched for once, then
+// advanced past the outputs finalizing since the last tick, against the latest l2 block time
+// like the search. Failures are logged without failing the tick, leaving the count as of the
+// last tick, and a failed search is only retried after the retry interval.
+func (m *Monitor) trackUnfinalizedOutputs(ctx context.Context, nextOutputIndex uint64) {
+	if !m.firstUnfinalizedKnown {
+		if m.clock.Now().Before(m.firstUnfinalizedRetryAt) {
+			return
+		}
+		index, err := m.findFirstUnfinalizedOutputIndex(ctx, m.faultProofWindow.Load())
+		if err != nil {
+			if ctx.Err() == nil {
+				m.log.Warn("failed to find first unfinalized output index", "err", err, "retry_in", firstUnfinalizedRetryInterval)
+				m.firstUnfinalizedRetryAt = m.clock.Now().Add(firstUnfinalizedRetryInterval)
+			}
+			return
+		}
+		m.firstUnfinalizedIndex = index
+		m.firstUnfinalizedKnown = true
+	}
+	if m.latestL2Time == 0 {
+		return
+	}
+
+	// outputs removed by an l1 reorg
+	if m.firstUnfinalizedIndex > nextOutputIndex {
+		m.firstUnfinalizedIndex = nextOutputIndex
+	}
+	for m.firstUnfinalizedIndex < nextOutputIndex {
+		index := m.firstUnfinalizedIndex
+		output, err := withRetries(ctx, m, "l1", "getL2Output", func(ctx context.Context) (bindings.TypesOutputProposal, error) {
+			return m.outputs.GetL2Output(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(index))
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				m.log.Warn("failed to query output for the unfinalized outputs", "index", index, "err", err)
+			}
+			return
+		}
+		if output.Timestamp.Uint64()+m.faultProofWindow.Load() >= m.latestL2Time {
+			break
+		}
+		m.firstUnfinalizedIndex++
+	}
+	m.unfinalizedOutputs.Set(float64(nextOutputIndex - m.firstUnfinalizedIndex))
+}
+
+// trackL2TimeSkew records the timestamp of the latest l2 block, which finalization times are
+// estimated from, and its skew from the local clock. Beyond the block time, the skew grows
+// with a drifting local clock or an l2 chain that stopped producing blocks.
+func (m *Monitor) trackL2TimeSkew(header *types.Header) {
+	m.latestL2Time = header.Time
+	skew := m.clock.Since(time.Unix(int64(header.Time), 0))
+	m.l2TimeSkewSeconds.Set(skew.Seconds())
+	if m.maxL2TimeSkew > 0 && (skew > m.maxL2TimeSkew || skew < -m.maxL2TimeSkew) {
+		m.log.Warn("local clock and latest l2 block time are skewed, finalization times may be off", "skew", skew.Truncate(time.Second), "l2_block_number", header.Number, "l2_block_time", header.Time, "max_skew", m.maxL2TimeSkew)
+	}
+}
+
+// logWaiting logs that the monitor is caught up, once on entering the waiting state and then
+// at most every wait log interval
+func (m *Monitor) logWaiting(nextOutputIndex uint64) {
+	now := m.clock.Now()
+	if !m.lastWaitLog.IsZero() && now.Sub(m.lastWaitLog) < m.waitLogInterval {
+		return
+	}
+	m.lastWaitLog = now
+
+	if nextOutputIndex == 0 {
+		m.log.Info("no outputs posted yet, waiting for the first output")
+	} else {
+		m.log.Info("waiting for next output", "index", m.currOutputIndex, "next_index", nextOutputIndex)
+	}
+}
+
+// trackProposer records increases of the next output index, flagging the proposer as
+// stalled once no output was posted for longer than the max output gap
+func (m *Monitor) trackProposer(nextOutputIndex uint64) {
+	now := m.clock.Now()
+	if m.lastOutputTime.IsZero() || nextOutputIndex > m.lastNextOutputIndex {
+		m.lastOutputTime = now
+	}
+	m.lastNextOutputIndex = nextOutputIndex
+
+	since := now.Sub(m.lastOutputTime)
+	m.secondsSinceLastOutput.Set(since.Seconds())
+	if m.maxOutputGap > 0 && since > m.maxOutputGap {
+		m.log.Error("proposer stalled, no new output posted", "next_index", nextOutputIndex, "since", since.Truncate(time.Second), "max_gap", m.maxOutputGap)
+		m.proposerStalled.Set(1)
+	} else {
+		m.proposerStalled.Set(0)
+	}
+
+	if m.submissionInterval > 0 && m.l2BlockTime > 0 {
+		m.secondsUntilNextExpectedOutput.Set(m.expectedOutputTime(nextOutputIndex).Sub(now).Seconds())
+	}
+}
+
+// expectedOutputTime returns the time the output at the index can be proposed, once the
+// timestamp of its l2 block has passed. Only known for the oracle's fixed schedule.
+func (m *Monitor) expectedOutputTime(index uint64) time.Time {
+	blocks := m.expectedL2BlockNumber(index) - m.startingBlockNumber
+	return time.Unix(int64(m.startingTimestamp+blocks*m.l2BlockTime), 0)
+}
+
+// catchUp validates batches of outputs concurrently while the lag exceeds the catch up
+// threshold. Results are applied in index order, stopping at the first output that could
+// not be checked or a mismatch the monitor halts on. As catching up may take many loop
+// intervals, the monitor is reported live after every batch applied in full.
+func (m *Monitor) catchUp(ctx context.Context, nextOutputIndex uint64) error {
+	m.log.Info("catching up on outputs", "index", m.currOutputIndex, "next_index", nextOutputIndex, "concurrency", m.maxConcurrency)
+	for nextOutputIndex-m.currOutputIndex > m.catchUpThreshold {
+		start, size := m.currOutputIndex, min(m.maxConcurrency, nextOutputIndex-m.currOutputIndex)
+		checks, errs := make([]*outputCheck, size), make([]error, size)
+
+		var wg sync.WaitGroup
+		for i := uint64(0); i < size; i++ {
+			wg.Add(1)
+			go func(i uint64) {
+				defer wg.Done()
+				checks[i], errs[i] = m.checkOutput(ctx, start+i)
+			}(i)
+		}
+		wg.Wait()
+
+		for i, check := range checks {
+			err := errs[i]
+			if err == nil {
+				check, err = m.confirmMismatch(ctx, check)
+			}
+			if m.skipStuckOutput(err) || m.skipInvalidatedOutput(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !m.applyCheck(ctx, check) {
+				return nil
+			}
+			m.logCatchUpProgress(nextOutputIndex)
+		}
+		m.outputIndexLag.Set(float64(nextOutputIndex - m.currOutputIndex))
+		m.lastSuccessfulTick.Store(m.clock.Now().UnixNano())
+		m.publishState()
+	}
+	m.log.Info("caught up on outputs", "index", m.currOutputIndex, "next_index", nextOutputIndex)
+	return nil
+}
+
+// checkOutput fetches the output at the given index and reconstructs its output root from L2.
+// Failures are logged and recorded before being returned, other than the context's
+// cancellation which is returned as is.
+func (m *Monitor) checkOutput(ctx context.Context, index uint64) (*outputCheck, error) {
+	start := time.Now()
+
+	// Fetch Output
+
+	output, err := withRetries(ctx, m, "l1", "getL2Output", func(ctx context.Context) (bindings.TypesOutputProposal, error) {
+		return m.outputs.GetL2Output(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(index))
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, errOutputInvalidated) {
+			m.log.Warn("output invalidated on l1, it is not validated", "index", index, "err", err)
+			return nil, err
+		}
+		if isExecutionReverted(err) {
+			m.log.Error("output query reverted", "index", index, "err", err)
+			return nil, fmt.Errorf("%w: %w", errOutputReverted, err)
+		}
+		m.log.Error("failed to query output", "index", index, "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l1", "getL2Output").Inc()
+		return nil, err
+	}
+
+	// A faulty node may respond with an empty output for an index reported as posted,
+	// which would otherwise be compared into a false mismatch
+	if output.OutputRoot == ([32]byte{}) || output.L2BlockNumber == nil || (index > 0 && output.L2BlockNumber.Sign() == 0) {
+		m.log.Error("zero output data in output response, skipping", "index", index, "output_root", common.Hash(output.OutputRoot).String(), "l2_block_number", output.L2BlockNumber)
+		m.zeroOutputData.Inc()
+		return nil, errZeroOutputData
+	}
+
+	// only informs logs and alerts, so the output is checked even if its proposer is unknown
+	proposer, err := withRetries(ctx, m, "l1", "proposer", func(ctx context.Context) (common.Address, error) {
+		return m.outputs.Proposer(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(index))
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		m.log.Warn("failed to query output proposer, leaving it empty", "index", index, "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l1", "proposer").Inc()
+	}
+	if expected := m.expectedL2BlockNumber(index); m.submissionInterval > 0 && output.L2BlockNumber.Uint64() != expected {
+		m.log.Error("output l2 block number does not match the submission interval", "index", index, "l2_block_number", output.L2BlockNumber, "expected_l2_block_number", expected)
+		m.outputBlockNumberAnomaly.Inc()
+	}
+	m.trackDuplicateOutputRoot(index, output)
+	l2Height, block, err := m.l2HeightAndBlock(ctx, output.L2BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		lag := output.L2BlockNumber.Uint64() - l2Height
+		waits := m.l2SyncWaits.Add(1)
+		m.l2SyncLagBlocks.Set(float64(lag))
+		m.l2SyncWaitTicks.Set(float64(waits))
+		if waits > m.maxSyncWaitTicks {
+			m.log.Error("l2 node is stuck behind the output", "index", index, "lag_blocks", lag, "waits", waits, "behavior", m.l2BehindBehavior)
+			if m.l2BehindBehavior == L2BehindBehaviorSkip || m.l2BehindBehavior == L2BehindBehaviorError {
+				return nil, errL2NodeStuck
+			}
+		} else {
+			m.log.Warn("l2 node is behind, waiting for sync...", "index", index, "lag_blocks", lag, "waits", waits)
+		}
+		return nil, errL2NodeBehind
+	}
+	m.l2SyncWaits.Store(0)
+	m.l2SyncLagBlocks.Set(0)
+	m.l2SyncWaitTicks.Set(0)
+
+	// A partially-synced node may respond with the block before its state is available,
+	// which would otherwise be reconstructed into a bogus output root
+	if block.Root() == (common.Hash{}) {
+		m.log.Error("zero state root in l2 block, state is unavailable", "index", index, "height", output.L2BlockNumber, "block_hash", block.Hash().String())
+		m.unavailableStateRoot.Inc()
+		return nil, errZeroStateRoot
+	}
+
+	if m.checkCanonicalBlock {
+		if err := m.checkBlockCanonical(ctx, index, block, l2Height); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fetch pre-image information for the output root from L2 to reconstruct. The
+	// proof is pinned to the block, which an l2 reorg may have replaced since it
+	// was fetched, checked against the canonical header fetched alongside
+
+	proof, header, err := m.l2ProofAndHeader(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	// A pruned node may respond without the storage root rather than failing,
+	// which would otherwise be reconstructed into a bogus output root
+	if proof.StorageHash == nil || *proof.StorageHash == (common.Hash{}) {
+		m.log.Error("empty storage root in proof response of l2ToL1MP contract", "height", output.L2BlockNumber, "address", m.messagePasserAddress.String())
+		m.emptyProofResponses.Inc()
+		return nil, errEmptyProof
+	}
+
+	// Skip reconstructing from a non-canonical block
+	if header.Hash() != block.Hash() {
+		m.log.Warn("l2 block changed during reconstruction, skipping", "index", index, "height", output.L2BlockNumber, "block_hash", block.Hash().String(), "canonical_hash", header.Hash().String())
+		m.reconstructionRaces.Inc()
+		return nil, errReconstructionRace
+	}
+
+	if m.verifyProofLocally {
+		if err := verifyAccountProof(block.Root(), m.messagePasserAddress, proof); err != nil {
+			m.log.Error("failed to verify proof response of l2ToL1MP contract", "index", index, "height", output.L2BlockNumber, "state_root", block.Root().String(), "address", m.messagePasserAddress.String(), "err", err)
+			m.invalidAccountProofs.Inc()
+			return nil, err
+		}
+	}
+
+	if m.l2SecondaryClient != nil {
+		if err := m.checkSecondaryL2(ctx, index, block, *proof.StorageHash); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reconstruct
+
+	valid, err := m.verifier.Verify(ctx, output, block, *proof.StorageHash)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		m.log.Error("failed to verify output", "index", index, "height", output.L2BlockNumber, "err", err)
+		return nil, err
+	}
+	var outputRoot eth.Bytes32
+	if reconstructor, ok := m.verifier.(OutputRootReconstructor); ok {
+		outputRoot = reconstructor.OutputRoot(block, *proof.StorageHash)
+	}
+	if m.debugReconstruction {
+		m.log.Debug("reconstructed output root",
+			"index", index,
+			"l2_block_number", block.NumberU64(),
+			"l2_block_hash", block.Hash().String(),
+			"state_root", block.Root().String(),
+			"message_passer_storage_root", proof.StorageHash.String(),
+			"output_root", outputRoot.String(),
+		)
+	}
+
+	if m.injectFault && index == m.injectFaultIndex {
+		m.log.Error("FAULT INJECTION: corrupting the reconstructed output root", "index", index)
+		for i := range outputRoot {
+			outputRoot[i] = ^output.OutputRoot[i]
+		}
+		valid = false
+	}
+
+	check := &outputCheck{index: index, output: output, proposer: proposer, block: block, outputRoot: outputRoot, valid: valid}
+	if m.rollupClient != nil {
+		rollupOutput, err := withRetries(ctx, m, "rollup", "outputAtBlock", func(ctx context.Context) (eth.OutputResponse, error) {
+			var rollupOutput eth.OutputResponse
+			err := m.rollupClient.CallContext(ctx, &rollupOutput, "optimism_outputAtBlock", hexutil.Uint64(block.NumberU64()))
+			return rollupOutput, err
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			m.log.Error("failed to query rollup node output", "height", output.L2BlockNumber, "err", err)
+			m.nodeConnectionFailures.WithLabelValues("rollup", "outputAtBlock").Inc()
+			return nil, err
+		}
+
+		check.rollupOutputRoot = &rollupOutput.OutputRoot
+		if rollupOutput.Version != m.outputVersion {
+			m.log.Error("rollup node reports a different output version than configured, check --"+OutputVersionFlagName, "index", index, "version", m.outputVersion.String(), "rollup_version", rollupOutput.Version.String())
+		}
+		if _, ok := m.verifier.(OutputRootReconstructor); ok && rollupOutput.OutputRoot != outputRoot {
+			m.log.Error("rollup node output root differs from the reconstructed output root",
+				"index", index,
+				"l2_block_number", block.NumberU64(),
+				"output_root", outputRoot.String(),
+				"rollup_output_root", rollupOutput.OutputRoot.String(),
+			)
+			m.rollupOutputMismatch.Inc()
+		}
+	}
+
+	m.outputValidationDuration.Observe(time.Since(start).Seconds())
+	return check, nil
+}
+
+// checkBlockCanonical cross-checks that the block fetched by number is canonical, fetching it
+// back by hash and, if the block has a child yet, checking the child links back to it
+func (m *Monitor) checkBlockCanonical(ctx context.Context, index uint64, block *types.Block, l2Height uint64) error {
+	byHash, err := withRetries(ctx, m, "l2", "headerByHash", func(ctx context.Context) (*types.Header, error) {
+		return m.l2Client.HeaderByHash(ctx, block.Hash())
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		m.log.Error("failed to query l2 header by hash", "hash", block.Hash().String(), "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "headerByHash").Inc()
+		return err
+	}
+	if byHash.Number.Cmp(block.Number()) != 0 {
+		m.log.Error("l2 block fetched by hash does not round-trip to its number, skipping", "index", index, "height", block.Number(), "block_hash", block.Hash().String(), "hash_height", byHash.Number)
+		m.nonCanonicalBlock.Inc()
+		return errNonCanonicalBlock
+	}
+
+	if block.NumberU64() >= l2Height {
+		return nil
+	}
+	child, err := withRetries(ctx, m, "l2", "headerByNumber", func(ctx context.Context) (*types.Header, error) {
+		return m.l2Client.HeaderByNumber(ctx, new(big.Int).Add(block.Number(), common.Big1))
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		m.log.Error("failed to query l2 child header", "height", block.NumberU64()+1, "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2", "headerByNumber").Inc()
+		return err
+	}
+	if child.ParentHash != block.Hash() {
+		m.log.Error("l2 block is not the parent of the canonical child, skipping", "index", index, "height", block.Number(), "block_hash", block.Hash().String(), "child_parent_hash", child.ParentHash.String())
+		m.nonCanonicalBlock.Inc()
+		return errNonCanonicalBlock
+	}
+	return nil
+}
+
+// checkSecondaryL2 checks that the secondary l2 node agrees with the l2 node on the block and
+// storage root the output root is reconstructed from, not trusting a single node
+func (m *Monitor) checkSecondaryL2(ctx context.Context, index uint64, block *types.Block, storageHash common.Hash) error {
+	header, err := withRetries(ctx, m, "l2Secondary", "headerByNumber", func(ctx context.Context) (*types.Header, error) {
+		return m.l2SecondaryClient.HeaderByNumber(ctx, block.Number())
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		m.log.Error("failed to query secondary l2 header", "height", block.Number(), "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2Secondary", "headerByNumber").Inc()
+		return err
+	}
+
+	proofBlock := rpc.BlockNumberOrHashWithHash(block.Hash(), true)
+	if m.proofByNumber {
+		proofBlock = rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block.Number().Int64()))
+	}
+	proof, err := withRetries(ctx, m, "l2Secondary", "getProof", func(ctx context.Context) (storageProof, error) {
+		var proof storageProof
+		err := m.l2SecondaryClient.Client().CallContext(ctx, &proof, "eth_getProof", m.messagePasserAddress, nil, proofBlock)
+		return proof, err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if isNotCanonical(err) {
+			return m.proofNotCanonical(block, err)
+		}
+		m.log.Error("failed to query secondary l2 proof", "height", block.Number(), "address", m.messagePasserAddress.String(), "err", err)
+		m.nodeConnectionFailures.WithLabelValues("l2Secondary", "getProof").Inc()
+		return err
+	}
+	if proof.StorageHash == nil || *proof.StorageHash == (common.Hash{}) {
+		m.log.Error("empty storage root in proof response of the secondary l2", "height", block.Number(), "address", m.messagePasserAddress.String())
+		m.emptyProofResponses.Inc()
+		return errEmptyProof
+	}
+
+	if header.Hash() != block.Hash() || header.Root != block.Root() || *proof.StorageHash != storageHash {
+		m.log.Error("l2 nodes disagree, skipping output",
+			"index", index,
+			"l2_block_number", block.NumberU64(),
+			"block_hash", block.Hash().String(),
+			"secondary_block_hash", header.Hash().String(),
+			"state_root", block.Root().String(),
+			"secondary_state_root", header.Root.String(),
+			"message_passer_storage_root", storageHash.String(),
+			"secondary_message_passer_storage_root", proof.StorageHash.String(),
+		)
+		m.l2NodeDisagreement.Inc()
+		return errL2NodeDisagreement
+	}
+	return nil
+}
+
+// expectedL2BlockNumber returns the l2 block number the oracle requires of the output at
+// the given index. The first output is proposed one interval after the starting block.
+func (m *Monitor) expectedL2BlockNumber(index uint64) uint64 {
+	return m.startingBlockNumber + (index+1)*m.submissionInterval
+}
+
+// finalizationTime returns the time the checked output becomes finalizable
+func (m *Monitor) finalizationTime(check *outputCheck) time.Time {
+	return time.Unix(int64(check.block.Time()+m.faultProofWindow.Load()), 0)
+}
+
+// logMismatch logs the mismatched output. The keys are relied upon by log based alerting
+// when emitted as json with --log.format, and must remain stable
+func (m *Monitor) logMismatch(check *outputCheck) {
+	ctx := []any{
+		"index", check.index,
+		"expected_output_root", check.outputRoot.String(),
+		"actual_output_root", common.Hash(check.output.OutputRoot).String(),
+		"proposer", check.proposer.String(),
+		"finalization_time", m.finalizationTime(check).String(),
+	}
+	if check.rollupOutputRoot != nil {
+		ctx = append(ctx, "rollup_output_root", check.rollupOutputRoot.String())
+	}
+	if m.inStartupGrace() {
+		m.log.Warn("output root mismatch during startup grace period", ctx...)
+		return
+	}
+	m.log.Error("output root mismatch!!!", ctx...)
+}
+
+// setLastMismatch replaces the labels of the lastMismatch gauge with the given mismatch,
+// truncating the roots to keep them readable on dashboards
+func (m *Monitor) setLastMismatch(check *outputCheck) {
+	m.lastMismatch.Reset()
+	m.lastMismatch.WithLabelValues(
+		strconv.FormatUint(check.index, 10),
+		check.block.Number().String(),
+		truncateHash(common.Hash(check.outputRoot)),
+		truncateHash(common.Hash(check.output.OutputRoot)),
+	).Set(1)
+}
+
+// truncateHash abbreviates the hash to its first 4 bytes
+func truncateHash(hash common.Hash) string {
+	return hexutil.Encode(hash[:4])
+}
+
+// notifyMismatch posts the mismatch to the webhook and slack if configured, unless already
+// notified of within the dedup window. Posts are made in the background, and failures are
+// logged and otherwise ignored
+func (m *Monitor) notifyMismatch(check *outputCheck) {
+	if m.webhook == nil && m.slack == nil {
+		return
+	}
+	if m.inStartupGrace() {
+		m.log.Debug("holding back mismatch notification during startup grace period", "index", check.index)
+		m.graceSuppressed[check.index] = struct{}{}
+		return
+	}
+	if m.alertDedup != nil && !m.alertDedup.allow(check.index, common.Hash(check.outputRoot)) {
+		m.log.Debug("skipping duplicate mismatch notification", "index", check.index)
+		return
+	}
+
+	event := mismatchEvent{
+		Index:              check.index,
+		ExpectedOutputRoot: common.Hash(check.outputRoot),
+		ActualOutputRoot:   common.Hash(check.output.OutputRoot),
+		Proposer:           check.proposer,
+		L2BlockNumber:      check.block.NumberU64(),
+		L2BlockHash:        check.block.Hash(),
+		FinalizationTime:   m.finalizationTime(check).Unix(),
+	}
+	if m.slack != nil && !m.slack.notify(event) {
+		m.log.Debug("skipping rate limited slack notification", "index", check.index)
+	}
+	if m.webhook == nil {
+		return
+	}
+	if m.webhookBatch != nil {
+		m.webhookBatch.add(event)
+		return
+	}
+	m.webhookPosts.post(event)
+}
+
+// countProposal counts the checked output by its proposer, unless the proposer is unknown
+func (m *Monitor) countProposal(check *outputCheck) {
+	if check.proposer != (common.Address{}) {
+		m.proposalsByProposer.WithLabelValues(check.proposer.String()).Inc()
+	}
+}
+
+// inStartupGrace returns true while mismatch notifications are held back after startup
+func (m *Monitor) inStartupGrace() bool {
+	return m.clock.Now().Before(m.startupGraceEnd)
+}
+
+// confirmGraceSuppressed re-checks the mismatches held back during the startup grace period
+// once it ended, notifying of those still mismatched. Indexes failing to be checked are kept
+// for the next loop, and a mismatch the monitor halts on is notified of by the loop itself.
+func (m *Monitor) confirmGraceSuppressed(ctx context.Context) {
+	if len(m.graceSuppressed) == 0 || m.inStartupGrace() {
+		return
+	}
+
+	for index := range m.graceSuppressed {
+		if _, ok := m.mismatchedIndexes[index]; !ok || (!m.continueOnMismatch && index == m.currOutputIndex) {
+			delete(m.graceSuppressed, index)
+			continue
+		}
+
+		check, err := m.checkOutput(ctx, index)
+		if err != nil {
+			m.log.Warn("failed to confirm mismatch held back during startup grace period", "index", index, "err", err)
+			continue
+		}
+		delete(m.graceSuppressed, index)
+		if !check.mismatched() {
+			m.log.Info("mismatch held back during startup grace period no longer mismatches", "index", index)
+			continue
+		}
+		m.logMismatch(check)
+		m.notifyMismatch(check)
+	}
+}
+
+func (m *Monitor) logValidated(check *outputCheck) {
+	m.log.Info("validated output", "index", check.index, "output_root", check.outputRoot.String(), "finalization_time", m.finalizationTime(check).String())
+}
+
+// persistCheckpoint writes the current output index to the checkpoint file, if configured, along
+// with the mismatched indexes skipped past when continuing on mismatches
+func (m *Monitor) persistCheckpoint() {
+	if m.checkpointPath == "" {
+		return
+	}
+	var mismatched []uint64
+	for index := range m.mismatchedIndexes {
+		if index < m.currOutputIndex {
+			mismatched = append(mismatched, index)
+		}
+	}
+	slices.Sort(mismatched)
+	if err := writeCheckpoint(m.checkpointPath, m.currOutputIndex, mismatched); err != nil {
+		m.log.Error("failed to write checkpoint", "path", m.checkpointPath, "err", err)
+	}
+}
+
+// L1Client returns the client of the l1 endpoint outputs are currently read from, which
+// changes as the monitor fails over between endpoints. It must not be closed while the
+// monitor is in use, and is closed along with the monitor unless passed in by the caller.
+func (m *Monitor) L1Client() *ethclient.Client {
+	return m.l1Client.activeClient()
+}
+
+// L2Client returns the client outputs are reconstructed from. It must not be closed while
+// the monitor is in use, and is closed along with the monitor unless passed in by the caller.
+func (m *Monitor) L2Client() *ethclient.Client {
+	return m.l2Client
+}
+
+// LastSuccessfulTick returns the time of the last tick completing without error, zero if none has
+func (m *Monitor) LastSuccessfulTick() time.Time {
+	if nanos := m.lastSuccessfulTick.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
+
+func (m *Monitor) Close(ctx context.Context) error {
+	if m.subscription != nil {
+		m.subscription.close()
+	}
+	if m.slack != nil {
+		m.slack.close(ctx)
+	}
+	if m.webhookPosts != nil {
+		m.webhookPosts.close(ctx)
+	}
+	if m.webhookBatch != nil {
+		m.webhookBatch.close(ctx)
+	}
+	if m.auditLog != nil {
+		if err := m.auditLog.close(); err != nil {
+			m.log.Error("failed to close audit log", "err", err)
+		}
+	}
+	if m.ownsClients {
+		m.l1Client.Close()
+		m.l2Client.Close()
+	}
+	if m.rollupClient != nil {
+		m.rollupClient.Close()
 	}
-	totalOutputsBig, err := m.l2OO.NextOutputIndex(callOpts)
+	if m.l2SecondaryClient != nil {
+		m.l2SecondaryClient.Close()
+	}
+	if m.health != nil {
+		return m.health.close()
+	}
+	return nil
+}
+
+func (m *Monitor) findFirstUnfinalizedOutputIndex(ctx context.Context, finalizationWindow uint64) (uint64, error) {
+	m.log.Info("searching for first unfinalized output")
+
+	totalOutputsBig, err := withRetries(ctx, m, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx})
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to query next output index: %w", err)
 	}
 
+	// On a new chain, the first output to be posted is the first unfinalized one
+	totalOutputs := totalOutputsBig.Uint64()
+	if totalOutputs == 0 {
+		m.log.Info("no outputs posted yet, starting from the first output")
+		return 0, nil
+	}
+
+	latestBlock, err := withRetries(ctx, m, "l2", "blockByNumber", func(ctx context.Context) (*types.Block, error) {
+		return m.l2Client.BlockByNumber(ctx, nil)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query latest block: %w", err)
+	}
+	m.trackL2TimeSkew(latestBlock.Header())
+
 	// Binary search the list of posted outputs
 
-	totalOutputs := totalOutputsBig.Uint64()
 	low, high := uint64(0), totalOutputs
 	for low < high {
 		mid := (low + high) / 2
-		output, err := m.l2OO.GetL2Output(callOpts, big.NewInt(int64(mid)))
+		output, err := withRetries(ctx, m, "l1", "getL2Output", func(ctx context.Context) (bindings.TypesOutputProposal, error) {
+			return m.outputs.GetL2Output(&bind.CallOpts{Context: ctx}, big.NewInt(int64(mid)))
+		})
 		if err != nil {
 			return 0, fmt.Errorf("failed to query output index %d: %w", mid, err)
 		}
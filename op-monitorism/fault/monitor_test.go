@@ -0,0 +1,895 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testOutputSource serves a fixed list of posted outputs
+type testOutputSource struct {
+	outputs []bindings.TypesOutputProposal
+}
+
+func (s *testOutputSource) NextOutputIndex(_ *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(int64(len(s.outputs))), nil
+}
+
+func (s *testOutputSource) GetL2Output(_ *bind.CallOpts, index *big.Int) (bindings.TypesOutputProposal, error) {
+	if !index.IsUint64() || index.Uint64() >= uint64(len(s.outputs)) {
+		return bindings.TypesOutputProposal{}, fmt.Errorf("output %s not posted", index)
+	}
+	return s.outputs[index.Uint64()], nil
+}
+
+func (s *testOutputSource) FinalizationPeriodSeconds(_ *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(604800), nil
+}
+
+func (s *testOutputSource) Proposer(_ *bind.CallOpts, _ *big.Int) (common.Address, error) {
+	return common.Address{}, nil
+}
+
+func TestFindFirstUnfinalizedOutputIndexWithoutOutputs(t *testing.T) {
+	m := newTestRetryMonitor(t, 0)
+	m.outputs = &testOutputSource{}
+
+	// no l2 client is set, which the search must not need without outputs to search
+	index, err := m.findFirstUnfinalizedOutputIndex(context.Background(), 604800)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), index)
+}
+
+// testCodeCaller serves contract code from a map of deployed contracts
+type testCodeCaller struct {
+	code map[common.Address][]byte
+}
+
+func (c *testCodeCaller) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	return c.code[contract], nil
+}
+
+func (c *testCodeCaller) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+// testProposalLogs serves an l1 chain missing a slot every 100 blocks, and the OutputProposed
+// logs of its blocks, each sent by the proposer of its block
+type testProposalLogs struct {
+	head      uint64
+	logs      []types.Log
+	proposers map[uint64]common.Address
+	queries   []ethereum.FilterQuery
+}
+
+func testL1BlockTime(number uint64) uint64 {
+	return 1_000_000 + l1SlotSeconds*(number+number/100)
+}
+
+func (s *testProposalLogs) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		number = new(big.Int).SetUint64(s.head)
+	}
+	return &types.Header{Number: number, Time: testL1BlockTime(number.Uint64())}, nil
+}
+
+func (s *testProposalLogs) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	s.queries = append(s.queries, q)
+	var logs []types.Log
+	for _, l := range s.logs {
+		if l.Topics[0] == q.Topics[0][0] && l.Topics[2] == q.Topics[2][0] && l.BlockNumber >= q.FromBlock.Uint64() && l.BlockNumber <= q.ToBlock.Uint64() {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+func (s *testProposalLogs) TransactionByHash(_ context.Context, _ common.Hash) (*types.Transaction, bool, error) {
+	return types.NewTx(&types.LegacyTx{}), false, nil
+}
+
+func (s *testProposalLogs) TransactionSender(_ context.Context, _ *types.Transaction, block common.Hash, _ uint) (common.Address, error) {
+	return s.proposers[new(big.Int).SetBytes(block.Bytes()).Uint64()], nil
+}
+
+func TestL2OutputOracleProposer(t *testing.T) {
+	oracleABI, err := bindings.L2OutputOracleMetaData.GetAbi()
+	require.NoError(t, err)
+	require.Equal(t, oracleABI.Events["OutputProposed"].ID, outputProposedTopic)
+
+	proposal := func(index int64, block uint64) types.Log {
+		return types.Log{
+			Topics:      []common.Hash{outputProposedTopic, {}, common.BigToHash(big.NewInt(index)), {}},
+			BlockNumber: block,
+			BlockHash:   common.BigToHash(new(big.Int).SetUint64(block)),
+		}
+	}
+	proposers := &testProposalLogs{
+		head: 200_000,
+		logs: []types.Log{proposal(1, 5_000), proposal(2, 150_000), proposal(2, 199_990)},
+		proposers: map[uint64]common.Address{
+			5_000:   common.HexToAddress("0x01"),
+			150_000: common.HexToAddress("0x02"),
+			199_990: common.HexToAddress("0x03"),
+		},
+	}
+	outputs := &l2OutputOracleOutputs{logs: proposers}
+
+	// found in a bounded range of blocks around the proposal time, rather than from genesis
+	proposer, err := outputs.proposerAt(context.Background(), big.NewInt(1), testL1BlockTime(5_000))
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x01"), proposer)
+	for _, q := range proposers.queries {
+		require.LessOrEqual(t, q.ToBlock.Uint64()-q.FromBlock.Uint64(), uint64(proposalRangeBlocks))
+	}
+
+	// the output proposed again after a deletion is the current one
+	proposer, err = outputs.proposerAt(context.Background(), big.NewInt(2), testL1BlockTime(199_990))
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x03"), proposer)
+
+	_, err = outputs.proposerAt(context.Background(), big.NewInt(3), testL1BlockTime(6_000))
+	require.ErrorIs(t, err, ethereum.NotFound)
+}
+
+func TestProposalBlockRange(t *testing.T) {
+	headers := &testProposalLogs{head: 200_000}
+	for _, block := range []uint64{0, 1, 99, 100, 5_000, 123_456, 199_999, 200_000} {
+		from, to, err := proposalBlockRange(context.Background(), headers, testL1BlockTime(block))
+		require.NoError(t, err)
+		require.LessOrEqual(t, from, block)
+		require.GreaterOrEqual(t, to, block)
+	}
+}
+
+func TestCheckContractCode(t *testing.T) {
+	deployed := common.Address{1}
+	caller := &testCodeCaller{code: map[common.Address][]byte{deployed: {0x60, 0x80}}}
+
+	require.NoError(t, checkContractCode(context.Background(), caller, "OptimismPortal", deployed))
+
+	err := checkContractCode(context.Background(), caller, "OptimismPortal", common.Address{2})
+	require.ErrorContains(t, err, "no contract deployed at the OptimismPortal address")
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	m := &Monitor{
+		log:                      testlog.Logger(t, log.LevelDebug),
+		maxConsecutiveMismatches: 3,
+		circuitBreakerOpen:       prometheus.NewGauge(prometheus.GaugeOpts{Name: "circuitBreakerOpen"}),
+	}
+
+	m.countConsecutiveMismatch()
+	m.countConsecutiveMismatch()
+	require.False(t, m.circuitOpen.Load())
+
+	m.countConsecutiveMismatch()
+	require.True(t, m.circuitOpen.Load())
+	require.Equal(t, float64(1), testutil.ToFloat64(m.circuitBreakerOpen))
+
+	m.resumeValidation()
+	require.False(t, m.circuitOpen.Load())
+	require.Zero(t, m.consecutiveMismatches)
+	require.Equal(t, float64(0), testutil.ToFloat64(m.circuitBreakerOpen))
+	require.False(t, m.state.Load().CircuitBreakerOpen)
+}
+
+func TestTrackProposerStall(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1_000_000, 0))
+	m := &Monitor{
+		log:                    testlog.Logger(t, log.LevelDebug),
+		clock:                  clk,
+		maxOutputGap:           time.Hour,
+		secondsSinceLastOutput: prometheus.NewGauge(prometheus.GaugeOpts{Name: "secondsSinceLastOutput"}),
+		proposerStalled:        prometheus.NewGauge(prometheus.GaugeOpts{Name: "proposerStalled"}),
+	}
+
+	m.trackProposer(10)
+	clk.AdvanceTime(30 * time.Minute)
+	m.trackProposer(10)
+	require.Equal(t, float64(1800), testutil.ToFloat64(m.secondsSinceLastOutput))
+	require.Equal(t, float64(0), testutil.ToFloat64(m.proposerStalled))
+
+	clk.AdvanceTime(time.Hour)
+	m.trackProposer(10)
+	require.Equal(t, float64(1), testutil.ToFloat64(m.proposerStalled))
+
+	m.trackProposer(11)
+	require.Equal(t, float64(0), testutil.ToFloat64(m.secondsSinceLastOutput))
+	require.Equal(t, float64(0), testutil.ToFloat64(m.proposerStalled))
+}
+
+func TestCompareOracles(t *testing.T) {
+	outputs := func(roots ...byte) *testOutputSource {
+		source := &testOutputSource{}
+		for i, root := range roots {
+			source.outputs = append(source.outputs, bindings.TypesOutputProposal{OutputRoot: [32]byte{root}, L2BlockNumber: big.NewInt(int64(i+1) * 100)})
+		}
+		return source
+	}
+
+	comparison, err := CompareOracles(context.Background(), outputs(1, 2, 3, 4), outputs(1, 2, 5), 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), comparison.Compared)
+	require.Len(t, comparison.Divergences, 1)
+	require.Equal(t, uint64(2), comparison.Divergences[0].Index)
+	require.Equal(t, common.Hash{5}, comparison.Divergences[0].OtherOutputRoot)
+
+	_, err = CompareOracles(context.Background(), outputs(1, 2, 3, 4), outputs(1, 2, 5), 1, 3)
+	require.ErrorContains(t, err, "has not been posted to the other oracle")
+}
+
+func TestLogWaitingIsRateLimited(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1_000_000, 0))
+	logger, logs := testlog.CaptureLogger(t, log.LevelInfo)
+	m := &Monitor{log: logger, clock: clk, waitLogInterval: time.Minute}
+
+	m.logWaiting(5)
+	m.logWaiting(5)
+	clk.AdvanceTime(30 * time.Second)
+	m.logWaiting(5)
+	require.Len(t, logs.FindLogs(testlog.NewMessageFilter("waiting for next output")), 1)
+
+	clk.AdvanceTime(30 * time.Second)
+	m.logWaiting(5)
+	require.Len(t, logs.FindLogs(testlog.NewMessageFilter("waiting for next output")), 2)
+}
+
+func TestSkipUnsampled(t *testing.T) {
+	m := &Monitor{
+		log:              testlog.Logger(t, log.LevelDebug),
+		sampleEveryN:     4,
+		unsampledOutputs: prometheus.NewCounter(prometheus.CounterOpts{Name: "unsampledOutputs"}),
+	}
+
+	m.currOutputIndex = 5
+	require.False(t, m.skipUnsampled(10))
+	require.Equal(t, uint64(8), m.currOutputIndex)
+
+	// sampled indexes are not skipped
+	require.False(t, m.skipUnsampled(10))
+	require.Equal(t, uint64(8), m.currOutputIndex)
+
+	// skipping stops at the next output index
+	m.currOutputIndex = 9
+	require.True(t, m.skipUnsampled(11))
+	require.Equal(t, uint64(11), m.currOutputIndex)
+	require.Equal(t, float64(5), testutil.ToFloat64(m.unsampledOutputs))
+}
+
+func TestStatus(t *testing.T) {
+	m := &Monitor{mismatchedIndexes: map[uint64]struct{}{}}
+	require.Equal(t, MonitorStatus{}, m.Status())
+
+	m.currOutputIndex, m.nextKnownIndex = 5, 7
+	m.mismatchedIndexes[4] = struct{}{}
+	m.publishState()
+	tickErr := fmt.Errorf("l1 unavailable")
+	m.lastTick.Store(&tickResult{time: time.Unix(1_000_000, 0), err: tickErr})
+
+	status := m.Status()
+	require.Equal(t, uint64(5), status.CurrOutputIndex)
+	require.Equal(t, uint64(7), status.NextKnownIndex)
+	require.True(t, status.IsMismatched)
+	require.Equal(t, time.Unix(1_000_000, 0), status.LastTickTime)
+	require.Equal(t, tickErr, status.LastError)
+	require.True(t, status.LastSuccessfulTick.IsZero())
+}
+
+func TestTrackUnfinalizedOutputs(t *testing.T) {
+	m := newTestRetryMonitor(t, 0)
+	m.latestL2Time = 1_000_000
+	m.faultProofWindow.Store(3600)
+	m.unfinalizedOutputs = prometheus.NewGauge(prometheus.GaugeOpts{Name: "unfinalizedOutputs"})
+	source := &testOutputSource{}
+	for i := 0; i < 4; i++ {
+		source.outputs = append(source.outputs, bindings.TypesOutputProposal{Timestamp: big.NewInt(int64(1_000_000 - 3600 + i*1800))})
+	}
+	m.outputs = source
+	m.firstUnfinalizedKnown = true
+
+	// the first output finalizes exactly now
+	m.trackUnfinalizedOutputs(context.Background(), 4)
+	require.Equal(t, float64(4), testutil.ToFloat64(m.unfinalizedOutputs))
+
+	m.latestL2Time += 3600
+	m.trackUnfinalizedOutputs(context.Background(), 4)
+	require.Equal(t, uint64(2), m.firstUnfinalizedIndex)
+	require.Equal(t, float64(2), testutil.ToFloat64(m.unfinalizedOutputs))
+
+	// rewound by an l1 reorg
+	m.trackUnfinalizedOutputs(context.Background(), 1)
+	require.Equal(t, float64(0), testutil.ToFloat64(m.unfinalizedOutputs))
+}
+
+// failingOutputSource fails every query of the next output index, counting them
+type failingOutputSource struct {
+	testOutputSource
+	calls int
+}
+
+func (s *failingOutputSource) NextOutputIndex(_ *bind.CallOpts) (*big.Int, error) {
+	s.calls++
+	return nil, errors.New("unavailable")
+}
+
+func TestTrackUnfinalizedOutputsRetriesFailedSearch(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1_000_000, 0))
+	m := newTestRetryMonitor(t, 0)
+	m.clock = clk
+	m.unfinalizedOutputs = prometheus.NewGauge(prometheus.GaugeOpts{Name: "unfinalizedOutputs"})
+	source := &failingOutputSource{}
+	m.outputs = source
+
+	// not searched again on every tick after a failure
+	m.trackUnfinalizedOutputs(context.Background(), 4)
+	m.trackUnfinalizedOutputs(context.Background(), 4)
+	require.Equal(t, 1, source.calls)
+
+	clk.AdvanceTime(firstUnfinalizedRetryInterval)
+	m.trackUnfinalizedOutputs(context.Background(), 4)
+	require.Equal(t, 2, source.calls)
+	require.False(t, m.firstUnfinalizedKnown)
+}
+
+func TestTrackDuplicateOutputRoot(t *testing.T) {
+	cache, err := lru.New(seenOutputRootsSize)
+	require.NoError(t, err)
+	m := &Monitor{
+		log:                 testlog.Logger(t, log.LevelDebug),
+		seenOutputRoots:     cache,
+		duplicateOutputRoot: prometheus.NewCounter(prometheus.CounterOpts{Name: "duplicateOutputRoot"}),
+	}
+	output := func(root byte, l2BlockNumber int64) bindings.TypesOutputProposal {
+		return bindings.TypesOutputProposal{OutputRoot: [32]byte{root}, L2BlockNumber: big.NewInt(l2BlockNumber)}
+	}
+
+	m.trackDuplicateOutputRoot(1, output(1, 100))
+	m.trackDuplicateOutputRoot(2, output(2, 200))
+	// re-checking the same output is not a duplicate
+	m.trackDuplicateOutputRoot(1, output(1, 100))
+	require.Equal(t, float64(0), testutil.ToFloat64(m.duplicateOutputRoot))
+
+	m.trackDuplicateOutputRoot(3, output(1, 300))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.duplicateOutputRoot))
+}
+
+func TestReset(t *testing.T) {
+	m := newTestRetryMonitor(t, 0)
+	m.outputs = &testOutputSource{outputs: make([]bindings.TypesOutputProposal, 10)}
+	m.currOutputIndex = 8
+	m.mismatchedIndexes = map[uint64]struct{}{7: {}}
+	m.circuitOpen.Store(true)
+	m.isCurrentlyMismatched = prometheus.NewGauge(prometheus.GaugeOpts{Name: "isCurrentlyMismatched"})
+	m.isCurrentlyMismatched.Set(1)
+	m.lastMismatch = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "lastMismatch"}, []string{"index"})
+	m.outputMismatchState = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "outputMismatchState"}, []string{"index"})
+	m.outputMismatchState.WithLabelValues("7").Set(1)
+	m.circuitBreakerOpen = prometheus.NewGauge(prometheus.GaugeOpts{Name: "circuitBreakerOpen"})
+	m.stuckTicksGauge = prometheus.NewGauge(prometheus.GaugeOpts{Name: "stuckTicks"})
+	m.monitorStuck = prometheus.NewGauge(prometheus.GaugeOpts{Name: "monitorStuck"})
+
+	require.ErrorContains(t, m.Reset(context.Background(), 11), "past the next output index")
+	require.Equal(t, uint64(8), m.currOutputIndex)
+
+	require.NoError(t, m.Reset(context.Background(), 2))
+	require.Equal(t, uint64(2), m.currOutputIndex)
+	require.Empty(t, m.mismatchedIndexes)
+	require.False(t, m.circuitOpen.Load())
+	require.Equal(t, float64(0), testutil.ToFloat64(m.isCurrentlyMismatched))
+	require.Zero(t, testutil.CollectAndCount(m.outputMismatchState))
+	require.Equal(t, uint64(2), m.state.Load().CurrOutputIndex)
+}
+
+func TestCheckpointMismatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	m := newTestRetryMonitor(t, 0)
+	m.checkpointPath = path
+	m.currOutputIndex = 5
+	m.mismatchedIndexes = map[uint64]struct{}{3: {}, 5: {}}
+
+	// the mismatch halted on is re-checked from the index itself
+	m.persistCheckpoint()
+	index, mismatched, err := readCheckpoint(path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), index)
+	require.Equal(t, []uint64{3}, mismatched)
+
+	// kept mismatched after a restart while failing to be re-checked
+	restarted := newTestRetryMonitor(t, 0)
+	restarted.outputs = &testOutputSource{}
+	restarted.currOutputIndex = index
+	restarted.mismatchedIndexes = make(map[uint64]struct{})
+	restarted.nodeConnectionFailures = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "nodeConnectionFailures"}, []string{"layer", "section"})
+	restarted.isCurrentlyMismatched = prometheus.NewGauge(prometheus.GaugeOpts{Name: "isCurrentlyMismatched"})
+	restarted.outputMismatchState = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "outputMismatchState"}, []string{"index"})
+	restarted.restoreMismatches(context.Background(), mismatched)
+	require.Contains(t, restarted.mismatchedIndexes, uint64(3))
+	require.Equal(t, float64(1), testutil.ToFloat64(restarted.isCurrentlyMismatched))
+	require.Equal(t, float64(1), testutil.ToFloat64(restarted.outputMismatchState.WithLabelValues("3")))
+}
+
+func TestSkipStuckOutput(t *testing.T) {
+	m := &Monitor{
+		log:                    testlog.Logger(t, log.LevelDebug),
+		currOutputIndex:        5,
+		l2BehindSkippedOutputs: prometheus.NewCounter(prometheus.CounterOpts{Name: "l2BehindSkippedOutputs"}),
+	}
+
+	// waiting by default
+	require.False(t, m.skipStuckOutput(errL2NodeStuck))
+
+	m.l2BehindBehavior = L2BehindBehaviorSkip
+	require.False(t, m.skipStuckOutput(errL2NodeBehind))
+	require.True(t, m.skipStuckOutput(errL2NodeStuck))
+	require.Equal(t, uint64(6), m.currOutputIndex)
+	require.Equal(t, float64(1), testutil.ToFloat64(m.l2BehindSkippedOutputs))
+}
+
+func TestSkipInvalidatedOutput(t *testing.T) {
+	m := &Monitor{
+		log:                   testlog.Logger(t, log.LevelDebug),
+		currOutputIndex:       5,
+		mismatchedIndexes:     map[uint64]struct{}{5: {}},
+		invalidatedOutputs:    prometheus.NewCounter(prometheus.CounterOpts{Name: "invalidatedOutputs"}),
+		isCurrentlyMismatched: prometheus.NewGauge(prometheus.GaugeOpts{Name: "isCurrentlyMismatched"}),
+		outputMismatchState:   prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "outputMismatchState"}, []string{"index"}),
+		lastMismatch:          prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "lastMismatch"}, []string{"index"}),
+	}
+	m.isCurrentlyMismatched.Set(1)
+	m.outputMismatchState.WithLabelValues("5").Set(1)
+
+	require.False(t, m.skipInvalidatedOutput(errOutputReverted))
+	require.Equal(t, uint64(5), m.currOutputIndex)
+
+	// the mismatch halted on is forgotten once its game is defeated
+	require.True(t, m.skipInvalidatedOutput(fmt.Errorf("%w: game resolved in favor of the challenger", errOutputInvalidated)))
+	require.Equal(t, uint64(6), m.currOutputIndex)
+	require.Empty(t, m.mismatchedIndexes)
+	require.Equal(t, float64(0), testutil.ToFloat64(m.isCurrentlyMismatched))
+	require.Equal(t, 0, testutil.CollectAndCount(m.outputMismatchState))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.invalidatedOutputs))
+}
+
+func TestTrackCaughtUp(t *testing.T) {
+	m := &Monitor{
+		log:           testlog.Logger(t, log.LevelDebug),
+		clock:         clock.NewDeterministicClock(time.Unix(1_000_000, 0)),
+		caughtUpGauge: prometheus.NewGauge(prometheus.GaugeOpts{Name: "caughtUp"}),
+	}
+
+	m.trackCaughtUp(10)
+	require.Equal(t, float64(0), testutil.ToFloat64(m.caughtUpGauge))
+	require.False(t, m.everCaughtUp)
+
+	m.currOutputIndex = 10
+	m.trackCaughtUp(10)
+	require.Equal(t, float64(1), testutil.ToFloat64(m.caughtUpGauge))
+	require.True(t, m.everCaughtUp)
+
+	m.trackCaughtUp(12)
+	require.Equal(t, float64(0), testutil.ToFloat64(m.caughtUpGauge))
+	require.True(t, m.everCaughtUp)
+}
+
+func TestLogCatchUpProgress(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1_000_000, 0))
+	logger, logs := testlog.CaptureLogger(t, log.LevelInfo)
+	m := &Monitor{log: logger, clock: clk, progressInterval: 10, progressIndex: 0, progressTime: clk.Now()}
+	filter := testlog.NewMessageFilter("catching up on outputs")
+
+	clk.AdvanceTime(5 * time.Second)
+	m.currOutputIndex = 9
+	m.logCatchUpProgress(100)
+	require.Empty(t, logs.FindLogs(filter))
+
+	m.currOutputIndex = 10
+	m.logCatchUpProgress(100)
+	require.Len(t, logs.FindLogs(filter), 1)
+	require.Equal(t, 2.0, logs.FindLog(filter).AttrValue("outputs_per_sec"))
+	require.Equal(t, uint64(10), m.progressIndex)
+
+	// caught up, nothing left to report
+	m.currOutputIndex = 100
+	m.logCatchUpProgress(100)
+	require.Len(t, logs.FindLogs(filter), 1)
+}
+
+// testRPCErrorResponse is the error of a json-rpc response
+type testRPCErrorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+var (
+	errTestMethodNotFound = &testRPCErrorResponse{Code: -32601, Message: "method not found"}
+	errTestReverted       = &testRPCErrorResponse{Code: 3, Message: "execution reverted"}
+)
+
+// serveTestRPC serves json-rpc requests with the results of respond
+func serveTestRPC(t *testing.T, respond func(req testRPCRequest) (any, *testRPCErrorResponse)) *ethclient.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req testRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if result, err := respond(req); err != nil {
+			resp["error"] = err
+		} else {
+			resp["result"] = result
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := ethclient.Dial(srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+const (
+	testSubmissionInterval = 10
+	testL2BlockTime        = 2
+	testL2StartTime        = 1_700_000_000
+)
+
+// testChain is an l2 chain served by an l2 node, with outputs of it posted to an
+// L2OutputOracle served by an l1 node
+type testChain struct {
+	t         *testing.T
+	oracleABI *abi.ABI
+	headers   []*types.Header
+	numbers   map[common.Hash]uint64
+
+	l1Client, l2Client *ethclient.Client
+
+	mu sync.Mutex
+	// number of outputs posted as of the latest l1 block, and as of earlier l1 blocks
+	posted, confirmed uint64
+	// number of proofs of the l2 block left to serve with a wrong storage root
+	badProofs map[uint64]int
+}
+
+func newTestChain(t *testing.T, outputs uint64) *testChain {
+	oracleABI, err := bindings.L2OutputOracleMetaData.GetAbi()
+	require.NoError(t, err)
+
+	c := &testChain{t: t, oracleABI: oracleABI, numbers: make(map[common.Hash]uint64), posted: outputs, confirmed: outputs, badProofs: make(map[uint64]int)}
+	for n := uint64(0); n <= (outputs+2)*testSubmissionInterval; n++ {
+		header := &types.Header{
+			Number:     new(big.Int).SetUint64(n),
+			Time:       testL2StartTime + n*testL2BlockTime,
+			Root:       common.Hash{0x1, byte(n)},
+			Difficulty: common.Big0,
+			TxHash:     types.EmptyTxsHash,
+			UncleHash:  types.EmptyUncleHash,
+		}
+		if n > 0 {
+			header.ParentHash = c.headers[n-1].Hash()
+		}
+		c.headers = append(c.headers, header)
+		c.numbers[header.Hash()] = n
+	}
+	c.l1Client = serveTestRPC(t, c.serveL1)
+	c.l2Client = serveTestRPC(t, c.serveL2)
+	return c
+}
+
+// post sets the number of outputs posted, as of every l1 block
+func (c *testChain) post(outputs uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.posted, c.confirmed = outputs, outputs
+}
+
+// deleteLatest sets the number of outputs posted as of the latest l1 block only, as after
+// outputs were deleted in a block not yet confirmed
+func (c *testChain) deleteLatest(outputs uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.posted = outputs
+}
+
+// corruptProofs serves the next proofs of the l2 block of the output with a wrong storage root
+func (c *testChain) corruptProofs(index uint64, proofs int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.badProofs[(index+1)*testSubmissionInterval] = proofs
+}
+
+func testStorageRoot(n uint64) common.Hash {
+	return common.Hash{0xaa, byte(n)}
+}
+
+func (c *testChain) output(index uint64) bindings.TypesOutputProposal {
+	header := c.headers[(index+1)*testSubmissionInterval]
+	return bindings.TypesOutputProposal{
+		OutputRoot:    reconstructOutputRoot(header.Root, testStorageRoot(header.Number.Uint64()), header.Hash()),
+		Timestamp:     new(big.Int).SetUint64(header.Time),
+		L2BlockNumber: header.Number,
+	}
+}
+
+func (c *testChain) serveL1(req testRPCRequest) (any, *testRPCErrorResponse) {
+	switch req.Method {
+	case "eth_blockNumber":
+		return hexutil.Uint64(100), nil
+	case "eth_getCode":
+		return hexutil.Bytes{0x1}, nil
+	case "eth_call":
+	default:
+		return nil, errTestMethodNotFound
+	}
+
+	var call struct {
+		Input hexutil.Bytes `json:"input"`
+		Data  hexutil.Bytes `json:"data"`
+	}
+	var block string
+	require.NoError(c.t, json.Unmarshal(req.Params[0], &call))
+	require.NoError(c.t, json.Unmarshal(req.Params[1], &block))
+	if len(call.Input) == 0 {
+		call.Input = call.Data
+	}
+	method, err := c.oracleABI.MethodById(call.Input[:4])
+	require.NoError(c.t, err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	posted := c.posted
+	if block != "latest" {
+		posted = c.confirmed
+	}
+
+	var result any
+	switch method.Name {
+	case "startingBlockNumber":
+		result = common.Big0
+	case "startingTimestamp":
+		result = big.NewInt(testL2StartTime)
+	case "SUBMISSION_INTERVAL":
+		result = big.NewInt(testSubmissionInterval)
+	case "L2_BLOCK_TIME":
+		result = big.NewInt(testL2BlockTime)
+	case "finalizationPeriodSeconds":
+		result = big.NewInt(7 * 24 * 60 * 60)
+	case "nextOutputIndex":
+		result = new(big.Int).SetUint64(posted)
+	case "getL2Output":
+		args, err := method.Inputs.Unpack(call.Input[4:])
+		require.NoError(c.t, err)
+		index := args[0].(*big.Int).Uint64()
+		if index >= posted {
+			return nil, errTestReverted
+		}
+		result = c.output(index)
+	default:
+		return nil, errTestReverted
+	}
+	packed, err := method.Outputs.Pack(result)
+	require.NoError(c.t, err)
+	return hexutil.Bytes(packed), nil
+}
+
+func (c *testChain) serveL2(req testRPCRequest) (any, *testRPCErrorResponse) {
+	latest := uint64(len(c.headers) - 1)
+	switch req.Method {
+	case "eth_blockNumber":
+		return hexutil.Uint64(latest), nil
+	case "eth_getBlockByNumber":
+		var number rpc.BlockNumber
+		require.NoError(c.t, json.Unmarshal(req.Params[0], &number))
+		if number < 0 {
+			return c.headers[latest], nil
+		}
+		if uint64(number) > latest {
+			return nil, nil
+		}
+		return c.headers[number], nil
+	case "eth_getBlockByHash":
+		var hash common.Hash
+		require.NoError(c.t, json.Unmarshal(req.Params[0], &hash))
+		if n, ok := c.numbers[hash]; ok {
+			return c.headers[n], nil
+		}
+		return nil, nil
+	case "eth_getProof":
+		var block rpc.BlockNumberOrHash
+		require.NoError(c.t, json.Unmarshal(req.Params[2], &block))
+		n := latest
+		if number, ok := block.Number(); ok && number >= 0 {
+			n = uint64(number)
+		} else if hash, ok := block.Hash(); ok {
+			n = c.numbers[hash]
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		storageRoot := testStorageRoot(n)
+		if c.badProofs[n] > 0 {
+			c.badProofs[n]--
+			storageRoot = common.Hash{0xbb}
+		}
+		return map[string]any{"storageHash": storageRoot, "accountProof": []string{}}, nil
+	default:
+		return nil, errTestMethodNotFound
+	}
+}
+
+// newMonitor creates a monitor of the chain, validating from the first output unless configured otherwise
+func (c *testChain) newMonitor(configure func(cfg *CLIConfig)) *Monitor {
+	cfg := CLIConfig{
+		L2OutputOracleAddress: common.Address{0x1},
+		InjectFaultAtIndex:    -1,
+		MaxOutputsPerTick:     10,
+		Clock:                 clock.NewDeterministicClock(time.Unix(int64(c.headers[len(c.headers)-1].Time), 0)),
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	m, err := NewMonitorWithClients(context.Background(), testlog.Logger(c.t, log.LevelDebug), metrics.With(prometheus.NewRegistry()), cfg, c.l1Client, c.l2Client)
+	require.NoError(c.t, err)
+	c.t.Cleanup(func() { require.NoError(c.t, m.Close(context.Background())) })
+	return m
+}
+
+func TestTick(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(cfg *CLIConfig)
+		run       func(t *testing.T, chain *testChain, m *Monitor)
+	}{
+		{
+			name: "ValidatesPostedOutputs",
+			run: func(t *testing.T, chain *testChain, m *Monitor) {
+				m.Run(context.Background())
+				require.NoError(t, m.Status().LastError)
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+				require.Equal(t, float64(4), testutil.ToFloat64(m.outputsValidatedTotal))
+				require.Equal(t, float64(0), testutil.ToFloat64(m.isCurrentlyMismatched))
+			},
+		},
+		{
+			name: "RewindsOnConfirmedL1Reorg",
+			run: func(t *testing.T, chain *testChain, m *Monitor) {
+				m.Run(context.Background())
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+
+				// a single lower next output index is not rewound to
+				chain.post(2)
+				m.Run(context.Background())
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+				chain.post(4)
+				m.Run(context.Background())
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+				require.Equal(t, float64(0), testutil.ToFloat64(m.l1Reorgs))
+
+				// rewound once seen on two consecutive ticks, then checking the outputs posted again
+				chain.post(2)
+				m.Run(context.Background())
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+				m.Run(context.Background())
+				require.Equal(t, uint64(2), m.Status().CurrOutputIndex)
+				require.Equal(t, float64(1), testutil.ToFloat64(m.l1Reorgs))
+				chain.post(4)
+				m.Run(context.Background())
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+				require.Equal(t, float64(6), testutil.ToFloat64(m.outputsValidatedTotal))
+			},
+		},
+		{
+			name:      "IgnoresTransientMismatch",
+			configure: func(cfg *CLIConfig) { cfg.MismatchConfirmations = 2 },
+			run: func(t *testing.T, chain *testChain, m *Monitor) {
+				chain.corruptProofs(1, 2)
+				m.Run(context.Background())
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+				require.Equal(t, float64(0), testutil.ToFloat64(m.mismatchedOutputIndexes))
+				require.Equal(t, float64(0), testutil.ToFloat64(m.isCurrentlyMismatched))
+			},
+		},
+		{
+			name:      "HaltsOnConfirmedMismatch",
+			configure: func(cfg *CLIConfig) { cfg.MismatchConfirmations = 2 },
+			run: func(t *testing.T, chain *testChain, m *Monitor) {
+				chain.corruptProofs(1, 3)
+				m.Run(context.Background())
+				require.Equal(t, uint64(1), m.Status().CurrOutputIndex)
+				require.True(t, m.Status().IsMismatched)
+				require.Equal(t, float64(1), testutil.ToFloat64(m.mismatchedOutputIndexes))
+				require.Equal(t, float64(1), testutil.ToFloat64(m.isCurrentlyMismatched))
+			},
+		},
+		{
+			name: "CorrectsOutOfRangeIndex",
+			configure: func(cfg *CLIConfig) {
+				cfg.L1ConfirmationBlocks = 1
+				cfg.MaxOutputsPerTick = 3
+			},
+			run: func(t *testing.T, chain *testChain, m *Monitor) {
+				m.Run(context.Background())
+				require.Equal(t, uint64(3), m.Status().CurrOutputIndex)
+
+				// deleted outputs revert once queried, clamping the index to the latest outputs
+				chain.deleteLatest(2)
+				m.Run(context.Background())
+				require.NoError(t, m.Status().LastError)
+				require.Equal(t, uint64(2), m.Status().CurrOutputIndex)
+				require.Equal(t, float64(1), testutil.ToFloat64(m.indexOutOfRangeCorrections))
+			},
+		},
+		{
+			name: "ContinuesOnMismatchAndCheckpoints",
+			configure: func(cfg *CLIConfig) {
+				cfg.ContinueOnMismatch = true
+				cfg.CheckpointPath = filepath.Join(t.TempDir(), "checkpoint")
+			},
+			run: func(t *testing.T, chain *testChain, m *Monitor) {
+				chain.corruptProofs(1, math.MaxInt)
+				m.Run(context.Background())
+				require.Equal(t, uint64(4), m.Status().CurrOutputIndex)
+				require.True(t, m.Status().IsMismatched)
+				index, mismatched, err := readCheckpoint(m.checkpointPath)
+				require.NoError(t, err)
+				require.Equal(t, uint64(4), index)
+				require.Equal(t, []uint64{1}, mismatched)
+
+				// restored mismatched after a restart from the checkpoint
+				restarted := chain.newMonitor(func(cfg *CLIConfig) {
+					cfg.ContinueOnMismatch = true
+					cfg.CheckpointPath = m.checkpointPath
+					cfg.StartOutputIndex = -1
+				})
+				require.Equal(t, uint64(4), restarted.Status().CurrOutputIndex)
+				require.True(t, restarted.Status().IsMismatched)
+				require.Equal(t, float64(1), testutil.ToFloat64(restarted.outputMismatchState.WithLabelValues("1")))
+
+				// recovered once re-checked against a node serving the right storage root
+				chain.corruptProofs(1, 0)
+				restarted.Run(context.Background())
+				require.False(t, restarted.Status().IsMismatched)
+				require.Equal(t, float64(1), testutil.ToFloat64(restarted.mismatchRecoveries))
+				require.Equal(t, float64(0), testutil.ToFloat64(restarted.isCurrentlyMismatched))
+				index, mismatched, err = readCheckpoint(m.checkpointPath)
+				require.NoError(t, err)
+				require.Equal(t, uint64(4), index)
+				require.Empty(t, mismatched)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newTestChain(t, 4)
+			tt.run(t, chain, chain.newMonitor(tt.configure))
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type chainMonitor struct {
+	name    string
+	monitor *Monitor
+	running atomic.Bool
+}
+
+// MultiMonitor runs a fault monitor for each configured chain from a single process.
+// Metrics of every chain carry a `chain` label and each chain tracks its own progress.
+type MultiMonitor struct {
+	log log.Logger
+
+	chains []*chainMonitor
+
+	health *healthServer
+
+	// ticks of each chain outlive a single loop iteration and
+	// are bound to this context instead, cancelled on Close
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewMultiMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIConfig) (_ *MultiMonitor, err error) {
+	log.Info("creating multi-chain fault monitor...", "chains", len(cfg.Chains))
+
+	// a single health server reports on all chains
+	var health *healthServer
+	if cfg.HealthEnabled {
+		health, err = startHealthServer(log, cfg.Clock, cfg.HealthAddr, cfg.HealthPort, time.Duration(cfg.LoopIntervalMs)*time.Millisecond, cfg.AdminEnabled)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				_ = health.close()
+			}
+		}()
+	}
+
+	var chains []*chainMonitor
+	for _, chain := range cfg.Chains {
+		chainLog := log.New("chain", chain.Name)
+		chainMetrics := withConstLabels(m, prometheus.Labels{"chain": chain.Name})
+		monitor, err := NewMonitor(ctx, chainLog, chainMetrics, cfg.ForChain(chain))
+		if err != nil {
+			for _, created := range chains {
+				_ = created.monitor.Close(ctx)
+			}
+			return nil, fmt.Errorf("failed to create monitor for chain %s: %w", chain.Name, err)
+		}
+		chains = append(chains, &chainMonitor{name: chain.Name, monitor: monitor})
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	mm := &MultiMonitor{log: log, chains: chains, health: health, ctx: runCtx, cancel: cancel}
+	if health != nil {
+		health.markReady(mm)
+	}
+	return mm, nil
+}
+
+// LastSuccessfulTick returns the oldest last successful tick of all chains, such that
+// the multi-chain monitor is only live while every chain is. Zero if a chain has yet
+// to tick successfully.
+func (mm *MultiMonitor) LastSuccessfulTick() time.Time {
+	var oldest time.Time
+	for _, chain := range mm.chains {
+		lastTick := chain.monitor.LastSuccessfulTick()
+		if lastTick.IsZero() {
+			return time.Time{}
+		}
+		if oldest.IsZero() || lastTick.Before(oldest) {
+			oldest = lastTick
+		}
+	}
+	return oldest
+}
+
+// Run ticks every chain concurrently without waiting for completion. A chain still
+// busy with its previous tick is skipped so a slow or stuck chain does not hold back
+// the others.
+func (mm *MultiMonitor) Run(_ context.Context) {
+	for _, chain := range mm.chains {
+		if !chain.running.CompareAndSwap(false, true) {
+			mm.log.Warn("previous tick still in progress, skipping", "chain", chain.name)
+			continue
+		}
+
+		mm.wg.Add(1)
+		go func(chain *chainMonitor) {
+			defer mm.wg.Done()
+			defer chain.running.Store(false)
+			chain.monitor.Run(mm.ctx)
+		}(chain)
+	}
+}
+
+// info returns the state of every chain by name
+func (mm *MultiMonitor) info() any {
+	chains := make(map[string]any, len(mm.chains))
+	for _, chain := range mm.chains {
+		chains[chain.name] = chain.monitor.info()
+	}
+	return chains
+}
+
+// SetLoopInterval updates the loop interval of every chain and of the health server
+func (mm *MultiMonitor) SetLoopInterval(interval time.Duration) {
+	for _, chain := range mm.chains {
+		chain.monitor.SetLoopInterval(interval)
+	}
+	if mm.health != nil {
+		mm.health.setLoopInterval(interval)
+	}
+}
+
+// resumeValidation resumes every chain paused by its circuit breaker
+func (mm *MultiMonitor) resumeValidation() {
+	for _, chain := range mm.chains {
+		chain.monitor.resumeValidation()
+	}
+}
+
+// resetValidation resets the named chain to the index
+func (mm *MultiMonitor) resetValidation(ctx context.Context, chain string, index uint64) error {
+	for _, c := range mm.chains {
+		if c.name == chain {
+			return c.monitor.Reset(ctx, index)
+		}
+	}
+	return fmt.Errorf("unknown chain %q", chain)
+}
+
+// Close cancels the in-flight ticks of every chain, waiting for them to return
+// before closing the chains' clients.
+func (mm *MultiMonitor) Close(ctx context.Context) error {
+	mm.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mm.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		mm.log.Warn("stopped waiting for in-flight ticks", "err", ctx.Err())
+	}
+
+	var errs []error
+	for _, chain := range mm.chains {
+		if err := chain.monitor.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close monitor for chain %s: %w", chain.name, err))
+		}
+	}
+	if mm.health != nil {
+		if err := mm.health.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close health server: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// OutputRootAtBlock is an output root reconstructed from an l2 block, along with its components
+type OutputRootAtBlock struct {
+	L2BlockNumber            uint64      `json:"l2_block_number"`
+	L2BlockHash              common.Hash `json:"l2_block_hash"`
+	StateRoot                common.Hash `json:"state_root"`
+	MessagePasserStorageRoot common.Hash `json:"message_passer_storage_root"`
+	OutputRoot               common.Hash `json:"output_root"`
+}
+
+// reconstructOutputRoot computes the output root committing to the l2 block
+func reconstructOutputRoot(stateRoot common.Hash, messagePasserStorageRoot common.Hash, blockHash common.Hash) eth.Bytes32 {
+	return eth.OutputRoot(&eth.OutputV0{
+		StateRoot:                eth.Bytes32(stateRoot),
+		MessagePasserStorageRoot: eth.Bytes32(messagePasserStorageRoot),
+		BlockHash:                blockHash,
+	})
+}
+
+// ReconstructOutputRoot reconstructs the output root of the l2 block at the given number, the
+// same way the monitor does, without requiring an l1 node. The message passer defaults to its
+// predeploy address if zero.
+func ReconstructOutputRoot(ctx context.Context, client *ethclient.Client, messagePasser common.Address, number *big.Int) (*OutputRootAtBlock, error) {
+	if messagePasser == (common.Address{}) {
+		messagePasser = predeploys.L2ToL1MessagePasserAddr
+	}
+
+	header, err := client.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query l2 block %d: %w", number, err)
+	}
+	if header.Root == (common.Hash{}) {
+		return nil, errZeroStateRoot
+	}
+
+	var proof storageProof
+	if err := client.Client().CallContext(ctx, &proof, "eth_getProof", messagePasser, nil, rpc.BlockNumberOrHashWithHash(header.Hash(), true)); err != nil {
+		return nil, fmt.Errorf("failed to query proof of the message passer: %w", err)
+	}
+	if proof.StorageHash == nil || *proof.StorageHash == (common.Hash{}) {
+		return nil, errEmptyProof
+	}
+
+	return &OutputRootAtBlock{
+		L2BlockNumber:            header.Number.Uint64(),
+		L2BlockHash:              header.Hash(),
+		StateRoot:                header.Root,
+		MessagePasserStorageRoot: *proof.StorageHash,
+		OutputRoot:               common.Hash(reconstructOutputRoot(header.Root, *proof.StorageHash, header.Hash())),
+	}, nil
+}
+
+// ReconstructOutputRootFromConfig dials the l2 node and reconstructs the output root of the configured block
+func ReconstructOutputRootFromConfig(ctx context.Context, cfg OutputRootCLIConfig) (*OutputRootAtBlock, error) {
+	tlsConfig, err := newClientTLSConfig(cfg.L2TLSCert, cfg.L2TLSKey, cfg.L2TLSCA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure l2 tls: %w", err)
+	}
+	proxy, err := parseProxyURL(cfg.RPCProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure rpc proxy: %w", err)
+	}
+	client, err := dialClient(ctx, cfg.L2NodeURL, cfg.L2RPCHeaders, tlsConfig, proxy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial l2: %w", err)
+	}
+	defer client.Close()
+
+	return ReconstructOutputRoot(ctx, client, cfg.MessagePasserAddress, new(big.Int).SetUint64(cfg.BlockNumber))
+}
@@ -0,0 +1,46 @@
+package fault
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var errInvalidAccountProof = errors.New("invalid account proof of the message passer")
+
+// verifyAccountProof verifies the account proof of the message passer against the state
+// root of the l2 block, so the storage root is proven rather than trusted from the node
+func verifyAccountProof(stateRoot common.Hash, address common.Address, proof storageProof) error {
+	if len(proof.AccountProof) == 0 {
+		return fmt.Errorf("%w: empty proof", errInvalidAccountProof)
+	}
+
+	proofDB := memorydb.New()
+	for _, node := range proof.AccountProof {
+		if err := proofDB.Put(crypto.Keccak256(node), node); err != nil {
+			return err
+		}
+	}
+	value, err := trie.VerifyProof(stateRoot, crypto.Keccak256(address.Bytes()), proofDB)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidAccountProof, err)
+	}
+	if len(value) == 0 {
+		return fmt.Errorf("%w: account %s not in state", errInvalidAccountProof, address)
+	}
+
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(value, &account); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidAccountProof, err)
+	}
+	if proof.StorageHash == nil || account.Root != *proof.StorageHash {
+		return fmt.Errorf("%w: proven storage root %s differs from the storage hash of the response", errInvalidAccountProof, account.Root)
+	}
+	return nil
+}
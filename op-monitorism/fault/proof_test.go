@@ -0,0 +1,49 @@
+package fault
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/stretchr/testify/require"
+)
+
+// proofList collects the nodes of a proof in the order of eth_getProof responses
+type proofList []hexutil.Bytes
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	address := common.Address{0x42}
+	storageRoot := common.Hash{0x01}
+
+	state := trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	account, err := rlp.EncodeToBytes(&types.StateAccount{Root: storageRoot, CodeHash: types.EmptyCodeHash.Bytes(), Balance: common.U2560})
+	require.NoError(t, err)
+	require.NoError(t, state.Update(crypto.Keccak256(address.Bytes()), account))
+	require.NoError(t, state.Update(crypto.Keccak256(common.Address{0x43}.Bytes()), account))
+	stateRoot := state.Hash()
+
+	var nodes proofList
+	require.NoError(t, state.Prove(crypto.Keccak256(address.Bytes()), &nodes))
+
+	require.NoError(t, verifyAccountProof(stateRoot, address, storageProof{StorageHash: &storageRoot, AccountProof: nodes}))
+
+	otherRoot := common.Hash{0x02}
+	require.ErrorIs(t, verifyAccountProof(stateRoot, address, storageProof{StorageHash: &otherRoot, AccountProof: nodes}), errInvalidAccountProof)
+	require.ErrorIs(t, verifyAccountProof(common.Hash{0x03}, address, storageProof{StorageHash: &storageRoot, AccountProof: nodes}), errInvalidAccountProof)
+	require.ErrorIs(t, verifyAccountProof(stateRoot, address, storageProof{StorageHash: &storageRoot}), errInvalidAccountProof)
+}
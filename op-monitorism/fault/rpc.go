@@ -0,0 +1,195 @@
+package fault
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/gorilla/websocket"
+)
+
+// isExecutionReverted returns true if the error is a contract call reverting, rather than
+// a failure to reach the node
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && strings.Contains(rpcErr.Error(), "execution reverted")
+}
+
+// isNotCanonical returns true if the error is the node rejecting an EIP-1898 block hash
+// parameter requiring a canonical block, as the block was reorged out
+func isNotCanonical(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && strings.Contains(rpcErr.Error(), "not currently canonical")
+}
+
+// isMethodNotFound returns true if the error is the node not serving the called method
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	msg := strings.ToLower(rpcErr.Error())
+	return rpcErr.ErrorCode() == -32601 || strings.Contains(msg, "method not found") || strings.Contains(msg, "does not exist")
+}
+
+// Classes of the errors failing a loop, counted by monitorErrorsTotal
+const (
+	errorClassNetwork  = "network"
+	errorClassContract = "contract"
+	errorClassData     = "data"
+	errorClassUnknown  = "unknown"
+)
+
+// classifyError returns whether the error is of reaching a node, a contract call reverting
+// or failing to be decoded, or a node responding with malformed or inconsistent data.
+// Network errors are expected noise, while the others point at a real problem.
+func classifyError(err error) string {
+	var (
+		netErr       net.Error
+		httpErr      rpc.HTTPError
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.UnmarshalTypeError
+	)
+	switch {
+	case isExecutionReverted(err), errors.Is(err, errOutputReverted), errors.Is(err, bind.ErrNoCode), strings.Contains(err.Error(), "abi: "):
+		return errorClassContract
+	case errors.Is(err, errZeroOutputData), errors.Is(err, errEmptyProof), errors.Is(err, errZeroStateRoot), errors.Is(err, errL2NodeDisagreement), errors.Is(err, errInvalidAccountProof),
+		errors.As(err, &syntaxErr), errors.As(err, &unmarshalErr), strings.Contains(err.Error(), "cannot unmarshal"):
+		return errorClassData
+	case errors.As(err, &netErr), errors.As(err, &httpErr), errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET):
+		return errorClassNetwork
+	default:
+		return errorClassUnknown
+	}
+}
+
+// dialClient dials the node, sending the headers with every request. A non-nil tls
+// config authenticates the connection with a client certificate, and a non-nil proxy
+// replaces the proxy of the environment
+func dialClient(ctx context.Context, endpoint string, headers http.Header, tlsConfig *tls.Config, proxy *url.URL) (*ethclient.Client, error) {
+	client, err := rpc.DialOptions(ctx, endpoint, clientOptions(headers, tlsConfig, proxy)...)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// clientOptions configures the transports of an rpc client. Both http and socks5 proxies are
+// supported, over http and websocket connections alike.
+func clientOptions(headers http.Header, tlsConfig *tls.Config, proxy *url.URL) []rpc.ClientOption {
+	options := []rpc.ClientOption{rpc.WithHeaders(headers)}
+	if tlsConfig == nil && proxy == nil {
+		return options
+	}
+
+	proxyFunc := http.ProxyFromEnvironment
+	if proxy != nil {
+		proxyFunc = http.ProxyURL(proxy)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = proxyFunc
+	return append(options,
+		rpc.WithHTTPClient(&http.Client{Transport: transport}),
+		rpc.WithWebsocketDialer(websocket.Dialer{
+			Proxy:            proxyFunc,
+			HandshakeTimeout: 45 * time.Second,
+			TLSClientConfig:  tlsConfig,
+		}),
+	)
+}
+
+// parseProxyURL parses the url of an http or socks5 proxy, nil if empty
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	if rawURL == "" {
+		return nil, nil
+	}
+	proxy, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return proxy, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https, socks5 or socks5h", proxy.Scheme)
+	}
+}
+
+// isDeterministic returns true if the error is the node answering the call, such as a revert,
+// a block reorged out, a missing object or an output invalidated on l1, which another attempt
+// would only repeat
+func isDeterministic(err error) bool {
+	return isExecutionReverted(err) || isNotCanonical(err) || errors.Is(err, ethereum.NotFound) || errors.Is(err, errOutputInvalidated)
+}
+
+// withRetries calls fn, retrying failed attempts up to the configured number of
+// times with an exponential backoff. Deterministic errors are returned without retrying.
+// Retries stop early if the context is cancelled or its deadline would pass before the
+// next attempt, returning the last error seen.
+// No call is made if the context is already cancelled. Every attempt is counted by
+// rpcCallsTotal, and failed attempts by rpcErrorsTotal. Each attempt is bounded by the
+// rpc timeout, if set, through the context passed to fn.
+func withRetries[T any](ctx context.Context, m *Monitor, layer, section string, fn func(ctx context.Context) (T, error)) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var res T
+		return res, err
+	}
+
+	call := func() (T, error) {
+		callCtx := ctx
+		if m.rpcTimeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, m.rpcTimeout)
+			defer cancel()
+		}
+
+		res, err := fn(callCtx)
+		m.rpcCallsTotal.WithLabelValues(layer, section).Inc()
+		if err != nil {
+			m.rpcErrorsTotal.WithLabelValues(layer, section).Inc()
+			if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+				m.log.Warn("rpc call timed out", "layer", layer, "section", section, "timeout", m.rpcTimeout)
+			}
+		}
+		return res, err
+	}
+
+	res, err := call()
+	for attempt := uint64(0); err != nil && attempt < m.rpcRetries; attempt++ {
+		if ctx.Err() != nil || isDeterministic(err) {
+			return res, err
+		}
+
+		backoff := m.rpcRetryBase << attempt
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return res, err
+		}
+
+		m.log.Warn("rpc call failed, retrying", "layer", layer, "section", section, "attempt", attempt+1, "backoff", backoff, "err", err)
+		m.rpcRetriesCount.WithLabelValues(layer, section).Inc()
+
+		select {
+		case <-ctx.Done():
+			return res, err
+		case <-time.After(backoff):
+		}
+		res, err = call()
+	}
+	return res, err
+}
@@ -0,0 +1,161 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRetryMonitor(t *testing.T, retries uint64) *Monitor {
+	return &Monitor{
+		log:          testlog.Logger(t, log.LevelDebug),
+		rpcRetries:   retries,
+		rpcRetryBase: time.Millisecond,
+		rpcRetriesCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rpcRetries",
+		}, []string{"layer", "section"}),
+		rpcCallsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rpcCallsTotal",
+		}, []string{"endpoint", "method"}),
+		rpcErrorsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rpcErrorsTotal",
+		}, []string{"endpoint", "method"}),
+	}
+}
+
+func TestWithRetries(t *testing.T) {
+	t.Run("SucceedsAfterTransientFailures", func(t *testing.T) {
+		m := newTestRetryMonitor(t, 3)
+		calls := 0
+		res, err := withRetries(context.Background(), m, "l1", "test", func(ctx context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, errors.New("transient")
+			}
+			return 42, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 42, res)
+		require.Equal(t, 3, calls)
+		require.Equal(t, float64(2), testutil.ToFloat64(m.rpcRetriesCount.WithLabelValues("l1", "test")))
+		require.Equal(t, float64(3), testutil.ToFloat64(m.rpcCallsTotal.WithLabelValues("l1", "test")))
+		require.Equal(t, float64(2), testutil.ToFloat64(m.rpcErrorsTotal.WithLabelValues("l1", "test")))
+	})
+
+	t.Run("GivesUpAfterMaxRetries", func(t *testing.T) {
+		m := newTestRetryMonitor(t, 2)
+		calls := 0
+		_, err := withRetries(context.Background(), m, "l2", "test", func(ctx context.Context) (int, error) {
+			calls++
+			return 0, errors.New("permanent")
+		})
+		require.ErrorContains(t, err, "permanent")
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("DoesNotRetryDeterministicErrors", func(t *testing.T) {
+		for _, failure := range []error{
+			testRPCError{code: 3, msg: "execution reverted"},
+			testRPCError{code: -32000, msg: "hash 0x01 is not currently canonical"},
+			fmt.Errorf("no output: %w", ethereum.NotFound),
+		} {
+			m := newTestRetryMonitor(t, 3)
+			calls := 0
+			_, err := withRetries(context.Background(), m, "l1", "test", func(ctx context.Context) (int, error) {
+				calls++
+				return 0, failure
+			})
+			require.ErrorIs(t, err, failure)
+			require.Equal(t, 1, calls)
+			require.Equal(t, float64(0), testutil.ToFloat64(m.rpcRetriesCount.WithLabelValues("l1", "test")))
+		}
+	})
+
+	t.Run("TimesOutHungCalls", func(t *testing.T) {
+		m := newTestRetryMonitor(t, 1)
+		m.rpcTimeout = 10 * time.Millisecond
+		calls := 0
+		_, err := withRetries(context.Background(), m, "l2", "test", func(ctx context.Context) (int, error) {
+			calls++
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("StopsOnCancelledContext", func(t *testing.T) {
+		m := newTestRetryMonitor(t, 5)
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, err := withRetries(ctx, m, "l2", "test", func(ctx context.Context) (int, error) {
+			calls++
+			cancel()
+			return 0, errors.New("failed")
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+}
+
+type testRPCError struct {
+	code int
+	msg  string
+}
+
+func (e testRPCError) Error() string  { return e.msg }
+func (e testRPCError) ErrorCode() int { return e.code }
+
+func TestIsExecutionReverted(t *testing.T) {
+	require.True(t, isExecutionReverted(testRPCError{code: 3, msg: "execution reverted"}))
+	require.True(t, isExecutionReverted(fmt.Errorf("call failed: %w", testRPCError{code: -32000, msg: "execution reverted: L2OutputOracle: index out of bounds"})))
+	require.False(t, isExecutionReverted(testRPCError{code: -32000, msg: "header not found"}))
+	require.False(t, isExecutionReverted(errors.New("execution reverted")))
+}
+
+func TestClassifyError(t *testing.T) {
+	require.Equal(t, errorClassNetwork, classifyError(fmt.Errorf("dial: %w", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED})))
+	require.Equal(t, errorClassNetwork, classifyError(context.DeadlineExceeded))
+	require.Equal(t, errorClassContract, classifyError(fmt.Errorf("%w: reverted", errOutputReverted)))
+	require.Equal(t, errorClassContract, classifyError(errors.New("abi: attempting to unmarshall an empty string while arguments are expected")))
+	require.Equal(t, errorClassData, classifyError(errEmptyProof))
+	require.Equal(t, errorClassData, classifyError(errors.New("json: cannot unmarshal string into Go value of type uint64")))
+	require.Equal(t, errorClassUnknown, classifyError(errors.New("something else")))
+}
+
+func TestDialClientThroughProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0xa"}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := parseProxyURL(proxy.URL)
+	require.NoError(t, err)
+	client, err := dialClient(context.Background(), "http://l2.invalid:8545", nil, nil, proxyURL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	chainID, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), chainID.Uint64())
+	require.Equal(t, "l2.invalid:8545", proxiedHost)
+
+	_, err = parseProxyURL("ftp://127.0.0.1:21")
+	require.ErrorContains(t, err, "unsupported proxy scheme")
+}
@@ -0,0 +1,127 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// slackRateLimit is the minimum interval between notifications of the same output index
+const slackRateLimit = time.Minute
+
+// slackMessage is the body of a Slack incoming webhook message. Discord accepts
+// the same body on the /slack suffix of its webhook URLs.
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Fields []slackField `json:"fields"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// slackNotifier posts mismatch events as Slack messages in the background, notifying
+// of the same output index at most once per rate limit interval
+type slackNotifier struct {
+	log     log.Logger
+	clock   clock.Clock
+	webhook *webhook
+
+	rateLimit time.Duration
+
+	mu       sync.Mutex
+	lastSent map[uint64]time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newSlackNotifier(log log.Logger, clk clock.Clock, url string) *slackNotifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &slackNotifier{
+		log:       log,
+		clock:     clk,
+		webhook:   newWebhook(log, url),
+		rateLimit: slackRateLimit,
+		lastSent:  make(map[uint64]time.Time),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// notify posts the event without blocking the caller. Returns false if the
+// index was already notified within the rate limit interval.
+func (s *slackNotifier) notify(event mismatchEvent) bool {
+	s.mu.Lock()
+	now := s.clock.Now()
+	if last, ok := s.lastSent[event.Index]; ok && now.Sub(last) < s.rateLimit {
+		s.mu.Unlock()
+		return false
+	}
+	s.lastSent[event.Index] = now
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ctx, cancel := context.WithTimeout(s.ctx, webhookPostTimeout)
+		defer cancel()
+		if err := s.webhook.post(ctx, slackMismatchMessage(event, now)); err != nil {
+			s.log.Error("failed to post mismatch to slack", "index", event.Index, "err", err)
+		}
+	}()
+	return true
+}
+
+// close waits for in-flight notifications, abandoning them once the context is done
+func (s *slackNotifier) close(ctx context.Context) {
+	defer s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+	}
+}
+
+func slackMismatchMessage(event mismatchEvent, now time.Time) slackMessage {
+	finalization := time.Unix(event.FinalizationTime, 0)
+	countdown := "finalized"
+	if until := finalization.Sub(now); until > 0 {
+		countdown = "in " + until.Truncate(time.Second).String()
+	}
+
+	return slackMessage{
+		Text: fmt.Sprintf(":rotating_light: output root mismatch at index %d", event.Index),
+		Attachments: []slackAttachment{{
+			Color: "danger",
+			Fields: []slackField{
+				{Title: "Index", Value: fmt.Sprint(event.Index), Short: true},
+				{Title: "L2 Block", Value: fmt.Sprint(event.L2BlockNumber), Short: true},
+				{Title: "Expected Output Root", Value: event.ExpectedOutputRoot.String()},
+				{Title: "Actual Output Root", Value: event.ActualOutputRoot.String()},
+				{Title: "Proposer", Value: event.Proposer.String()},
+				{Title: "Finalization", Value: fmt.Sprintf("%s (%s)", finalization.UTC().Format(time.RFC3339), countdown)},
+			},
+		}},
+	}
+}
@@ -0,0 +1,114 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// outputResubscribeDelay is the wait before resubscribing after the subscription drops
+const outputResubscribeDelay = 10 * time.Second
+
+// outputSubscription validates outputs as soon as they are proposed, by subscribing to the
+// oracle's OutputProposed logs over a websocket l1 endpoint. The monitor keeps polling on
+// every loop regardless, covering the time the subscription is down.
+type outputSubscription struct {
+	client   *ethclient.Client
+	filterer *bindings.L2OutputOracleFilterer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// subscribeOutputs starts the subscription to outputs proposed to the oracle. Dispute games
+// are not supported, leaving the monitor to poll.
+func (m *Monitor) subscribeOutputs(ctx context.Context, url string, cfg CLIConfig) error {
+	if m.outputsContract != "L2OutputOracle" {
+		m.log.Warn("output subscriptions are only supported for the L2OutputOracle, polling instead", "outputs_contract", m.outputsContract)
+		return nil
+	}
+
+	tlsConfig, err := cfg.l1TLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to configure l1 tls: %w", err)
+	}
+	proxy, err := parseProxyURL(cfg.RPCProxyURL)
+	if err != nil {
+		return fmt.Errorf("failed to configure rpc proxy: %w", err)
+	}
+	client, err := dialClient(ctx, url, cfg.L1RPCHeaders, tlsConfig, proxy)
+	if err != nil {
+		return fmt.Errorf("failed to dial l1 websocket: %w", err)
+	}
+	if err := checkChainID(ctx, client, "l1 websocket", cfg.L1ChainID); err != nil {
+		client.Close()
+		return err
+	}
+	filterer, err := bindings.NewL2OutputOracleFilterer(m.outputsAddress, client)
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to bind to the L2OutputOracle: %w", err)
+	}
+
+	subCtx, cancel := context.WithCancel(context.Background())
+	m.subscription = &outputSubscription{client: client, filterer: filterer, cancel: cancel}
+	m.subscription.wg.Add(1)
+	go func() {
+		defer m.subscription.wg.Done()
+		m.watchOutputs(subCtx)
+	}()
+	return nil
+}
+
+// watchOutputs runs a tick for every proposed output, resubscribing whenever the subscription drops
+func (m *Monitor) watchOutputs(ctx context.Context) {
+	for {
+		sink := make(chan *bindings.L2OutputOracleOutputProposed, 16)
+		sub, err := m.subscription.filterer.WatchOutputProposed(&bind.WatchOpts{Context: ctx}, sink, nil, nil, nil)
+		if err == nil {
+			m.log.Info("subscribed to proposed outputs")
+			m.outputSubscriptionActive.Set(1)
+			err = m.handleProposedOutputs(ctx, sub, sink)
+			sub.Unsubscribe()
+			m.outputSubscriptionActive.Set(0)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.log.Warn("output subscription dropped, polling until resubscribed", "err", err)
+		m.outputSubscriptionDrops.Inc()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(outputResubscribeDelay):
+		}
+	}
+}
+
+func (m *Monitor) handleProposedOutputs(ctx context.Context, sub event.Subscription, sink <-chan *bindings.L2OutputOracleOutputProposed) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return err
+		case proposed := <-sink:
+			m.log.Info("output proposed", "index", proposed.L2OutputIndex, "l2_block_number", proposed.L2BlockNumber)
+			m.Run(ctx)
+		}
+	}
+}
+
+func (s *outputSubscription) close() {
+	s.cancel()
+	s.wg.Wait()
+	s.client.Close()
+}
@@ -0,0 +1,50 @@
+package fault
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// newClientTLSConfig loads the client certificate and CA of a mutual-TLS endpoint. Returns
+// nil if none are set, in which case the default TLS configuration applies. The CA is optional,
+// defaulting to the system's certificate pool.
+func newClientTLSConfig(certPath, keyPath, caPath string) (*tls.Config, error) {
+	if certPath == "" && keyPath == "" && caPath == "" {
+		return nil, nil
+	}
+	if (certPath == "") != (keyPath == "") {
+		return nil, errors.New("tls cert and key must be set together")
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if certPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls cert: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if caPath != "" {
+		ca, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in tls ca %s", caPath)
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+func (c CLIConfig) l1TLSConfig() (*tls.Config, error) {
+	return newClientTLSConfig(c.L1TLSCert, c.L1TLSKey, c.L1TLSCA)
+}
+
+func (c CLIConfig) l2TLSConfig() (*tls.Config, error) {
+	return newClientTLSConfig(c.L2TLSCert, c.L2TLSKey, c.L2TLSCA)
+}
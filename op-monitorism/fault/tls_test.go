@@ -0,0 +1,29 @@
+package fault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientTLSConfig(t *testing.T) {
+	t.Run("UnsetIsNil", func(t *testing.T) {
+		cfg, err := newClientTLSConfig("", "", "")
+		require.NoError(t, err)
+		require.Nil(t, cfg)
+	})
+
+	t.Run("CertWithoutKey", func(t *testing.T) {
+		_, err := newClientTLSConfig("tls.crt", "", "")
+		require.ErrorContains(t, err, "must be set together")
+	})
+
+	t.Run("InvalidCA", func(t *testing.T) {
+		caPath := filepath.Join(t.TempDir(), "ca.crt")
+		require.NoError(t, os.WriteFile(caPath, []byte("not a certificate"), 0o600))
+		_, err := newClientTLSConfig("", "", caPath)
+		require.ErrorContains(t, err, "no certificates found")
+	})
+}
@@ -0,0 +1,35 @@
+package fault
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// OutputVerifier verifies a posted output against the l2 block it commits to and the storage
+// root of the message passer at that block. Forks computing output roots differently can
+// provide their own implementation with CLIConfig.OutputVerifier.
+type OutputVerifier interface {
+	Verify(ctx context.Context, output bindings.TypesOutputProposal, block *types.Block, messagePasserStorageRoot common.Hash) (bool, error)
+}
+
+// OutputRootReconstructor is optionally implemented by verifiers reconstructing the output root,
+// which is then reported as the expected output root of mismatches
+type OutputRootReconstructor interface {
+	OutputRoot(block *types.Block, messagePasserStorageRoot common.Hash) eth.Bytes32
+}
+
+// OutputV0Verifier is the default verifier, reconstructing the output root as an eth.OutputV0
+type OutputV0Verifier struct{}
+
+func (OutputV0Verifier) OutputRoot(block *types.Block, messagePasserStorageRoot common.Hash) eth.Bytes32 {
+	return reconstructOutputRoot(block.Root(), messagePasserStorageRoot, block.Hash())
+}
+
+func (v OutputV0Verifier) Verify(_ context.Context, output bindings.TypesOutputProposal, block *types.Block, messagePasserStorageRoot common.Hash) (bool, error) {
+	return v.OutputRoot(block, messagePasserStorageRoot) == eth.Bytes32(output.OutputRoot), nil
+}
@@ -0,0 +1,35 @@
+package fault
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/multisig/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/eth"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputV0Verifier(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(100), Root: common.Hash{1}})
+	storageRoot := common.Hash{2}
+	outputRoot := eth.OutputRoot(&eth.OutputV0{
+		StateRoot:                eth.Bytes32(block.Root()),
+		MessagePasserStorageRoot: eth.Bytes32(storageRoot),
+		BlockHash:                block.Hash(),
+	})
+
+	var verifier OutputV0Verifier
+	require.Equal(t, outputRoot, verifier.OutputRoot(block, storageRoot))
+
+	valid, err := verifier.Verify(context.Background(), bindings.TypesOutputProposal{OutputRoot: outputRoot}, block, storageRoot)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	valid, err = verifier.Verify(context.Background(), bindings.TypesOutputProposal{OutputRoot: [32]byte{3}}, block, storageRoot)
+	require.NoError(t, err)
+	require.False(t, valid)
+}
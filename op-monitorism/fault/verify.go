@@ -0,0 +1,269 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RangeSummary is the result of validating a range of outputs
+type RangeSummary struct {
+	StartIndex        uint64   `json:"start_index"`
+	EndIndex          uint64   `json:"end_index"`
+	Checked           uint64   `json:"checked"`
+	Mismatches        uint64   `json:"mismatches"`
+	MismatchedIndexes []uint64 `json:"mismatched_indexes"`
+	// outputs invalidated on l1, which are not checked
+	Invalidated uint64 `json:"invalidated"`
+}
+
+// VerifyRange validates every output in the inclusive range of indexes a single time, recording
+// mismatches rather than halting on them. Outputs invalidated on l1 are counted and skipped. An error is returned if any output could not be checked.
+func (m *Monitor) VerifyRange(ctx context.Context, start, end uint64) (*RangeSummary, error) {
+	if start > end {
+		return nil, fmt.Errorf("start index %d is after end index %d", start, end)
+	}
+
+	nextOutputIndex, err := withRetries(ctx, m, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx})
+	})
+	if err != nil {
+		m.nodeConnectionFailures.WithLabelValues("l1", "nextOutputIndex").Inc()
+		return nil, fmt.Errorf("failed to query next output index: %w", err)
+	}
+	if end >= nextOutputIndex.Uint64() {
+		return nil, fmt.Errorf("end index %d has not been posted, next output index is %d", end, nextOutputIndex)
+	}
+	m.highestOutputIndex.WithLabelValues("known").Set(float64(nextOutputIndex.Uint64()))
+
+	summary := &RangeSummary{StartIndex: start, EndIndex: end, MismatchedIndexes: []uint64{}}
+	for index := start; index <= end; index++ {
+		check, err := m.checkOutput(ctx, index)
+		if err == nil {
+			check, err = m.confirmMismatch(ctx, check)
+		}
+		if errors.Is(err, errOutputInvalidated) {
+			m.invalidatedOutputs.Inc()
+			summary.Invalidated++
+			continue
+		}
+		if err != nil {
+			return summary, fmt.Errorf("failed to check output %d: %w", index, err)
+		}
+
+		summary.Checked++
+		m.highestOutputIndex.WithLabelValues("checked").Set(float64(index))
+		if check.mismatched() {
+			m.logMismatch(check)
+			m.mismatchedOutputIndexes.Inc()
+			m.isCurrentlyMismatched.Set(1)
+			m.outputMismatchState.WithLabelValues(strconv.FormatUint(index, 10)).Set(1)
+			m.setLastMismatch(check)
+			m.notifyMismatch(check)
+			summary.Mismatches++
+			summary.MismatchedIndexes = append(summary.MismatchedIndexes, index)
+			continue
+		}
+
+		m.logValidated(check)
+		m.outputsValidatedTotal.Inc()
+	}
+
+	return summary, nil
+}
+
+// MismatchRange is the contiguous range of mismatched outputs ending at the index a backwards scan
+// started from. LastValidIndex is the matching output preceding the range, unset if the scan reached
+// its lower bound without finding one.
+type MismatchRange struct {
+	EarliestIndex  uint64  `json:"earliest_index"`
+	LatestIndex    uint64  `json:"latest_index"`
+	Mismatches     uint64  `json:"mismatches"`
+	LastValidIndex *uint64 `json:"last_valid_index,omitempty"`
+}
+
+// FindEarliestMismatch scans backwards from a mismatched output, down to the given lower bound, for the
+// earliest output of the contiguous range of mismatches it belongs to. An error is returned if the
+// starting output is not mismatched or any output could not be checked.
+func (m *Monitor) FindEarliestMismatch(ctx context.Context, from, lowest uint64) (*MismatchRange, error) {
+	if lowest > from {
+		return nil, fmt.Errorf("lowest index %d is after starting index %d", lowest, from)
+	}
+
+	check, err := m.checkOutput(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check output %d: %w", from, err)
+	}
+	if !check.mismatched() {
+		return nil, fmt.Errorf("output %d is not mismatched", from)
+	}
+	m.logMismatch(check)
+
+	mismatches := &MismatchRange{EarliestIndex: from, LatestIndex: from, Mismatches: 1}
+	for index := from; index > lowest; {
+		index--
+		check, err := m.checkOutput(ctx, index)
+		if err != nil {
+			return mismatches, fmt.Errorf("failed to check output %d: %w", index, err)
+		}
+		if !check.mismatched() {
+			m.logValidated(check)
+			mismatches.LastValidIndex = &index
+			break
+		}
+
+		m.logMismatch(check)
+		mismatches.EarliestIndex = index
+		mismatches.Mismatches++
+	}
+
+	return mismatches, nil
+}
+
+// FinalizedOutputCheck is the result of validating the latest finalized output
+type FinalizedOutputCheck struct {
+	Index              uint64      `json:"index"`
+	L2BlockNumber      uint64      `json:"l2_block_number"`
+	OutputRoot         common.Hash `json:"output_root"`
+	ExpectedOutputRoot common.Hash `json:"expected_output_root"`
+	Mismatched         bool        `json:"mismatched"`
+}
+
+// VerifyLatestFinalized validates the most recent output past its finalization window a single
+// time, as a quick check of whether finalized outputs can be trusted. An error is returned if no
+// output is finalized yet or the output could not be checked.
+func (m *Monitor) VerifyLatestFinalized(ctx context.Context) (*FinalizedOutputCheck, error) {
+	firstUnfinalizedIndex, err := m.findFirstUnfinalizedOutputIndex(ctx, m.faultProofWindow.Load())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find first unfinalized output index: %w", err)
+	}
+	if firstUnfinalizedIndex == 0 {
+		return nil, fmt.Errorf("no output is finalized yet")
+	}
+
+	index := firstUnfinalizedIndex - 1
+	check, err := m.checkOutput(ctx, index)
+	if err == nil {
+		check, err = m.confirmMismatch(ctx, check)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check output %d: %w", index, err)
+	}
+
+	m.highestOutputIndex.WithLabelValues("checked").Set(float64(index))
+	result := &FinalizedOutputCheck{
+		Index:              index,
+		L2BlockNumber:      check.output.L2BlockNumber.Uint64(),
+		OutputRoot:         common.Hash(check.output.OutputRoot),
+		ExpectedOutputRoot: common.Hash(check.outputRoot),
+		Mismatched:         check.mismatched(),
+	}
+	if result.Mismatched {
+		m.logMismatch(check)
+		m.mismatchedOutputIndexes.Inc()
+		m.isCurrentlyMismatched.Set(1)
+		m.outputMismatchState.WithLabelValues(strconv.FormatUint(index, 10)).Set(1)
+		m.setLastMismatch(check)
+		m.notifyMismatch(check)
+		return result, nil
+	}
+
+	m.logValidated(check)
+	m.outputsValidatedTotal.Inc()
+	return result, nil
+}
+
+// HistoryScan is the result of scanning the output history. Only the outputs checked by this run
+// are counted, the report also holding the mismatches found by the runs it resumed from.
+type HistoryScan struct {
+	StartIndex        uint64   `json:"start_index"`
+	NextOutputIndex   uint64   `json:"next_output_index"`
+	Checked           uint64   `json:"checked"`
+	Mismatches        uint64   `json:"mismatches"`
+	MismatchedIndexes []uint64 `json:"mismatched_indexes"`
+	// outputs invalidated on l1, which are not checked
+	Invalidated uint64 `json:"invalidated"`
+}
+
+// ScanHistory validates every output posted so far a single time, appending a record of each
+// mismatch to the report rather than halting on it. Outputs invalidated on l1 are counted and skipped.
+// With a checkpoint path, the next index to check is persisted after every output and the scan
+// resumes from it. An error is returned if any output could not be checked, from which the scan can
+// be resumed.
+func (m *Monitor) ScanHistory(ctx context.Context, reportPath string) (*HistoryScan, error) {
+	var start uint64
+	if m.checkpointPath != "" {
+		checkpoint, _, err := readCheckpoint(m.checkpointPath)
+		switch {
+		case err == nil:
+			m.log.Info("resuming history scan from checkpoint", "path", m.checkpointPath, "index", checkpoint)
+			start = checkpoint
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+	}
+
+	nextOutputIndex, err := withRetries(ctx, m, "l1", "nextOutputIndex", func(ctx context.Context) (*big.Int, error) {
+		return m.outputs.NextOutputIndex(&bind.CallOpts{Context: ctx})
+	})
+	if err != nil {
+		m.nodeConnectionFailures.WithLabelValues("l1", "nextOutputIndex").Inc()
+		return nil, fmt.Errorf("failed to query next output index: %w", err)
+	}
+	m.highestOutputIndex.WithLabelValues("known").Set(float64(nextOutputIndex.Uint64()))
+
+	report, err := openAuditLog(reportPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open report: %w", err)
+	}
+	defer report.close()
+
+	scan := &HistoryScan{StartIndex: start, NextOutputIndex: nextOutputIndex.Uint64(), MismatchedIndexes: []uint64{}}
+	m.progressIndex, m.progressTime = start, m.clock.Now()
+	for index := start; index < scan.NextOutputIndex; index++ {
+		check, err := m.checkOutput(ctx, index)
+		if err == nil {
+			check, err = m.confirmMismatch(ctx, check)
+		}
+		if errors.Is(err, errOutputInvalidated) {
+			m.invalidatedOutputs.Inc()
+			scan.Invalidated++
+			m.currOutputIndex = index + 1
+			m.persistCheckpoint()
+			continue
+		}
+		if err != nil {
+			return scan, fmt.Errorf("failed to check output %d: %w", index, err)
+		}
+
+		scan.Checked++
+		m.highestOutputIndex.WithLabelValues("checked").Set(float64(index))
+		if check.mismatched() {
+			m.logMismatch(check)
+			m.mismatchedOutputIndexes.Inc()
+			m.isCurrentlyMismatched.Set(1)
+			m.outputMismatchState.WithLabelValues(strconv.FormatUint(index, 10)).Set(1)
+			m.setLastMismatch(check)
+			if err := report.record(check); err != nil {
+				return scan, fmt.Errorf("failed to record mismatch of output %d: %w", index, err)
+			}
+			scan.Mismatches++
+			scan.MismatchedIndexes = append(scan.MismatchedIndexes, index)
+		} else {
+			m.logValidated(check)
+			m.outputsValidatedTotal.Inc()
+		}
+
+		m.currOutputIndex = index + 1
+		m.persistCheckpoint()
+		m.logCatchUpProgress(scan.NextOutputIndex)
+	}
+
+	return scan, nil
+}
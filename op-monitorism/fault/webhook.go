@@ -0,0 +1,193 @@
+package fault
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	webhookAttempts = 3
+	webhookTimeout  = 5 * time.Second
+	webhookBackoff  = time.Second
+
+	// webhookPostTimeout bounds a post made in the background, including all of its attempts
+	webhookPostTimeout = webhookAttempts * (webhookTimeout + webhookBackoff)
+
+	// webhookSignatureHeader carries the HMAC-SHA256 of the uncompressed JSON body, hex encoded
+	// and prefixed with sha256=, when a signing secret is configured
+	webhookSignatureHeader = "X-Monitorism-Signature"
+)
+
+// mismatchEvent is the body posted to the webhook when an output root mismatch is detected
+type mismatchEvent struct {
+	Index              uint64         `json:"index"`
+	ExpectedOutputRoot common.Hash    `json:"expected_output_root"`
+	ActualOutputRoot   common.Hash    `json:"actual_output_root"`
+	Proposer           common.Address `json:"proposer"`
+	L2BlockNumber      uint64         `json:"l2_block_number"`
+	L2BlockHash        common.Hash    `json:"l2_block_hash"`
+	FinalizationTime   int64          `json:"finalization_time"`
+}
+
+// webhook posts JSON events to a configured URL
+type webhook struct {
+	log    log.Logger
+	url    string
+	client *http.Client
+
+	attempts int
+	backoff  time.Duration
+
+	// gzip-compresses the body of every post
+	gzip bool
+	// signs the body of every post if set
+	signingSecret []byte
+}
+
+func newWebhook(log log.Logger, url string) *webhook {
+	return &webhook{
+		log:      log,
+		url:      url,
+		client:   &http.Client{Timeout: webhookTimeout},
+		attempts: webhookAttempts,
+		backoff:  webhookBackoff,
+	}
+}
+
+// post sends the JSON encoded event, retrying failed attempts. The last error is returned
+// once all attempts are exhausted or the context is cancelled.
+func (w *webhook) post(ctx context.Context, event any) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	var signature string
+	if len(w.signingSecret) > 0 {
+		signature = signBody(w.signingSecret, body)
+	}
+	if w.gzip {
+		if body, err = gzipBody(body); err != nil {
+			return fmt.Errorf("failed to compress event: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = w.send(ctx, body, signature)
+		if err == nil || attempt >= w.attempts {
+			return err
+		}
+
+		w.log.Warn("failed to post webhook, retrying", "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(w.backoff):
+		}
+	}
+}
+
+// webhookPoster posts events in the background, so a slow or failing endpoint never holds
+// up the loop
+type webhookPoster struct {
+	log     log.Logger
+	webhook *webhook
+
+	// bounds the in-flight posts, cancelled once closed
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWebhookPoster(log log.Logger, hook *webhook) *webhookPoster {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhookPoster{log: log, webhook: hook, ctx: ctx, cancel: cancel}
+}
+
+// post posts the event without blocking the caller
+func (p *webhookPoster) post(event mismatchEvent) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ctx, cancel := context.WithTimeout(p.ctx, webhookPostTimeout)
+		defer cancel()
+		if err := p.webhook.post(ctx, event); err != nil {
+			p.log.Error("failed to post mismatch to webhook", "index", event.Index, "err", err)
+		}
+	}()
+}
+
+// close waits for in-flight posts, abandoning them once the context is done
+func (p *webhookPoster) close(ctx context.Context) {
+	defer p.cancel()
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+	}
+}
+
+func (w *webhook) send(ctx context.Context, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if signature != "" {
+		req.Header.Set(webhookSignatureHeader, signature)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signBody returns the signature of the body sent in the signature header, for receivers to
+// authenticate the post by computing it with the shared secret
+func signBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
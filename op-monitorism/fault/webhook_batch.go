@@ -0,0 +1,113 @@
+package fault
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// mismatchBatch is the body posted to the webhook when batching mismatch events
+type mismatchBatch struct {
+	Events []mismatchEvent `json:"events"`
+}
+
+// webhookBatcher collects mismatch events, posting them to the webhook as a single batch
+// every interval, or as soon as the batch reaches its max size if bounded
+type webhookBatcher struct {
+	log     log.Logger
+	webhook *webhook
+
+	interval time.Duration
+	// max number of events in a batch, unbounded if zero
+	size int
+
+	mu      sync.Mutex
+	pending []mismatchEvent
+	full    chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWebhookBatcher(log log.Logger, hook *webhook, interval time.Duration, size int) *webhookBatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &webhookBatcher{
+		log:      log,
+		webhook:  hook,
+		interval: interval,
+		size:     size,
+		full:     make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.loop()
+	}()
+	return b
+}
+
+// add queues the event for the next batch without blocking the caller
+func (b *webhookBatcher) add(event mismatchEvent) {
+	b.mu.Lock()
+	b.pending = append(b.pending, event)
+	full := b.size > 0 && len(b.pending) >= b.size
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *webhookBatcher) loop() {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.full:
+		}
+		b.flush(b.ctx)
+	}
+}
+
+// flush posts the pending events, at most the max batch size per post
+func (b *webhookBatcher) flush(ctx context.Context) {
+	b.mu.Lock()
+	events := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	for len(events) > 0 {
+		batch := events
+		if b.size > 0 && len(batch) > b.size {
+			batch = batch[:b.size]
+		}
+		events = events[len(batch):]
+
+		postCtx, cancel := context.WithTimeout(ctx, webhookPostTimeout)
+		err := b.webhook.post(postCtx, mismatchBatch{Events: batch})
+		cancel()
+		if err != nil {
+			b.log.Error("failed to post mismatch batch to webhook", "events", len(batch), "first_index", batch[0].Index, "err", err)
+		}
+	}
+}
+
+// close stops the batching and posts the remaining events, abandoning them once the
+// context is done
+func (b *webhookBatcher) close(ctx context.Context) {
+	b.cancel()
+	b.wg.Wait()
+	b.flush(ctx)
+}
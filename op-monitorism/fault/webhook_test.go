@@ -0,0 +1,224 @@
+package fault
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRetriesFailedPosts(t *testing.T) {
+	var requests int
+	var received mismatchEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < webhookAttempts {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer srv.Close()
+
+	hook := newWebhook(testlog.Logger(t, log.LevelDebug), srv.URL)
+	hook.backoff = 0
+
+	event := mismatchEvent{Index: 7, L2BlockNumber: 100, FinalizationTime: 1700000000}
+	require.NoError(t, hook.post(context.Background(), event))
+	require.Equal(t, webhookAttempts, requests)
+	require.Equal(t, event, received)
+}
+
+func TestWebhookSignsPosts(t *testing.T) {
+	secret := []byte("secret")
+	for _, compressed := range []bool{false, true} {
+		var received mismatchEvent
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// verified as a receiver would, over the decompressed JSON body
+			reader := io.Reader(r.Body)
+			if r.Header.Get("Content-Encoding") == "gzip" {
+				zr, err := gzip.NewReader(r.Body)
+				require.NoError(t, err)
+				reader = zr
+			}
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(body)
+			signature, ok := strings.CutPrefix(r.Header.Get(webhookSignatureHeader), "sha256=")
+			require.True(t, ok)
+			sent, err := hex.DecodeString(signature)
+			require.NoError(t, err)
+			if !hmac.Equal(mac.Sum(nil), sent) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			require.NoError(t, json.Unmarshal(body, &received))
+		}))
+
+		hook := newWebhook(testlog.Logger(t, log.LevelDebug), srv.URL)
+		hook.gzip = compressed
+		hook.signingSecret = secret
+		require.NoError(t, hook.post(context.Background(), mismatchEvent{Index: 7}))
+		require.Equal(t, uint64(7), received.Index)
+
+		// rejected once signed with another secret
+		hook.signingSecret = []byte("other")
+		hook.attempts = 1
+		require.Error(t, hook.post(context.Background(), mismatchEvent{Index: 7}))
+		srv.Close()
+	}
+}
+
+func TestWebhookGivesUpAfterAttempts(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	hook := newWebhook(testlog.Logger(t, log.LevelDebug), srv.URL)
+	hook.backoff = 0
+
+	require.Error(t, hook.post(context.Background(), mismatchEvent{}))
+	require.Equal(t, webhookAttempts, requests)
+}
+
+func TestWebhookPosterPostsInBackground(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan mismatchEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		var event mismatchEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer srv.Close()
+
+	// returns while the endpoint is still responding
+	poster := newWebhookPoster(testlog.Logger(t, log.LevelDebug), newWebhook(testlog.Logger(t, log.LevelDebug), srv.URL))
+	poster.post(mismatchEvent{Index: 7})
+	require.Empty(t, received)
+
+	// flushed on close
+	close(release)
+	poster.close(context.Background())
+	require.Len(t, received, 1)
+	require.Equal(t, uint64(7), (<-received).Index)
+}
+
+func TestSlackNotifierRateLimitsIndexes(t *testing.T) {
+	received := make(chan slackMessage, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received <- msg
+	}))
+	defer srv.Close()
+
+	slack := newSlackNotifier(testlog.Logger(t, log.LevelDebug), clock.SystemClock, srv.URL)
+	require.True(t, slack.notify(mismatchEvent{Index: 7}))
+	require.False(t, slack.notify(mismatchEvent{Index: 7}))
+	require.True(t, slack.notify(mismatchEvent{Index: 8}))
+	slack.close(context.Background())
+
+	require.Len(t, received, 2)
+	msg := <-received
+	require.Len(t, msg.Attachments, 1)
+	require.Equal(t, "danger", msg.Attachments[0].Color)
+}
+
+func TestWebhookBatcherPostsBatches(t *testing.T) {
+	received := make(chan mismatchBatch, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		var batch mismatchBatch
+		require.NoError(t, json.NewDecoder(zr).Decode(&batch))
+		received <- batch
+	}))
+	defer srv.Close()
+
+	hook := newWebhook(testlog.Logger(t, log.LevelDebug), srv.URL)
+	hook.gzip = true
+	batcher := newWebhookBatcher(testlog.Logger(t, log.LevelDebug), hook, time.Hour, 2)
+
+	// posted once the batch is full
+	batcher.add(mismatchEvent{Index: 7})
+	batcher.add(mismatchEvent{Index: 8})
+	batch := <-received
+	require.Len(t, batch.Events, 2)
+	require.Equal(t, uint64(7), batch.Events[0].Index)
+
+	// remaining events are posted on close
+	batcher.add(mismatchEvent{Index: 9})
+	batcher.close(context.Background())
+	require.Len(t, received, 1)
+	batch = <-received
+	require.Len(t, batch.Events, 1)
+	require.Equal(t, uint64(9), batch.Events[0].Index)
+}
+
+func TestAlertDedup(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1700000000, 0))
+	dedup := newAlertDedup(clk, time.Minute)
+	root := common.Hash{1}
+
+	require.True(t, dedup.allow(7, root))
+	require.False(t, dedup.allow(7, root))
+	require.True(t, dedup.allow(7, common.Hash{2}))
+
+	clk.AdvanceTime(time.Minute)
+	require.True(t, dedup.allow(7, root))
+
+	dedup.clear(7)
+	require.True(t, dedup.allow(7, root))
+
+	// suppressed until cleared without a window
+	dedup = newAlertDedup(clk, 0)
+	require.True(t, dedup.allow(7, root))
+	clk.AdvanceTime(time.Hour)
+	require.False(t, dedup.allow(7, root))
+}
+
+func TestStartupGraceHoldsBackNotifications(t *testing.T) {
+	clk := clock.NewDeterministicClock(time.Unix(1700000000, 0))
+	m := &Monitor{
+		log:               testlog.Logger(t, log.LevelDebug),
+		clock:             clk,
+		slack:             &slackNotifier{},
+		startupGraceEnd:   clk.Now().Add(time.Minute),
+		graceSuppressed:   make(map[uint64]struct{}),
+		mismatchedIndexes: make(map[uint64]struct{}),
+	}
+
+	// held back without notifying
+	m.notifyMismatch(&outputCheck{index: 7})
+	require.Contains(t, m.graceSuppressed, uint64(7))
+
+	// kept until the grace period ends, then dropped once the mismatch cleared
+	m.confirmGraceSuppressed(context.Background())
+	require.Contains(t, m.graceSuppressed, uint64(7))
+	clk.AdvanceTime(time.Minute)
+	require.False(t, m.inStartupGrace())
+	m.confirmGraceSuppressed(context.Background())
+	require.Empty(t, m.graceSuppressed)
+}
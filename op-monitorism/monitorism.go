@@ -4,7 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -23,6 +30,11 @@ import (
 
 const (
 	LoopIntervalMsecFlagName = "loop.interval.msec"
+	LoopJitterMsecFlagName   = "loop.jitter.msec"
+	LoopIntervalFileFlagName = "loop.interval.file"
+
+	// upper bound on waiting for an in-flight tick when stopping
+	drainTimeout = 30 * time.Second
 )
 
 type Monitor interface {
@@ -30,13 +42,36 @@ type Monitor interface {
 	Close(context.Context) error
 }
 
+// LoopIntervalObserver is implemented by monitors depending on the loop interval, such as to
+// report on their liveness. They are notified of the interval on start and on every change.
+type LoopIntervalObserver interface {
+	SetLoopInterval(interval time.Duration)
+}
+
 type cliApp struct {
 	log     log.Logger
 	stopped atomic.Bool
 
+	loopJitterMs     uint64
+	loopIntervalFile string
+
+	// drives the loop, the system clock unless injected by tests
+	clock clock.Clock
+
+	// guards the loop interval and the worker, which is re-created when the interval changes
+	mu             sync.Mutex
 	loopIntervalMs uint64
 	worker         *clock.LoopFn
 
+	// held for the duration of a tick, so ticks of a replaced worker never overlap the new one
+	tickMu sync.Mutex
+
+	// ticks run under this context rather than the worker's, letting a replaced worker
+	// finish its in-flight tick. Cancelled on stop.
+	runCtx    context.Context
+	runCancel context.CancelFunc
+	sighup    chan os.Signal
+
 	monitor Monitor
 
 	registry   *prometheus.Registry
@@ -45,28 +80,53 @@ type cliApp struct {
 }
 
 func NewCliApp(ctx *cli.Context, log log.Logger, registry *prometheus.Registry, monitor Monitor) (cliapp.Lifecycle, error) {
+	return NewCliAppWithClock(ctx, log, registry, monitor, clock.SystemClock)
+}
+
+// NewCliAppWithClock creates the app with the loop driven by the given clock, such as a
+// deterministic clock advanced manually by tests
+func NewCliAppWithClock(ctx *cli.Context, log log.Logger, registry *prometheus.Registry, monitor Monitor, clk clock.Clock) (cliapp.Lifecycle, error) {
 	loopIntervalMs := ctx.Uint64(LoopIntervalMsecFlagName)
 	if loopIntervalMs == 0 {
 		return nil, errors.New("zero loop interval configured")
 	}
+	loopJitterMs := ctx.Uint64(LoopJitterMsecFlagName)
+	if loopJitterMs >= loopIntervalMs {
+		return nil, errors.New("loop jitter must be less than the loop interval")
+	}
 
 	return &cliApp{
-		log:            log,
-		loopIntervalMs: loopIntervalMs,
-		monitor:        monitor,
-		registry:       registry,
-		metricsCfg:     opmetrics.ReadCLIConfig(ctx),
+		log:              log,
+		loopIntervalMs:   loopIntervalMs,
+		loopJitterMs:     loopJitterMs,
+		loopIntervalFile: ctx.String(LoopIntervalFileFlagName),
+		clock:            clk,
+		monitor:          monitor,
+		registry:         registry,
+		metricsCfg:       opmetrics.ReadCLIConfig(ctx),
 	}, nil
 }
 
 func DefaultCLIFlags(envVarPrefix string) []cli.Flag {
 	defaultFlags := append(oplog.CLIFlags(envVarPrefix), opmetrics.CLIFlags(envVarPrefix)...)
-	return append(defaultFlags, &cli.Uint64Flag{
-		Name:    LoopIntervalMsecFlagName,
-		Usage:   "Loop interval of the monitor in milliseconds",
-		Value:   60_000,
-		EnvVars: opservice.PrefixEnvVar(envVarPrefix, "LOOP_INTERVAL_MSEC"),
-	})
+	return append(defaultFlags,
+		&cli.Uint64Flag{
+			Name:    LoopIntervalMsecFlagName,
+			Usage:   "Loop interval of the monitor in milliseconds",
+			Value:   60_000,
+			EnvVars: opservice.PrefixEnvVar(envVarPrefix, "LOOP_INTERVAL_MSEC"),
+		},
+		&cli.Uint64Flag{
+			Name:    LoopJitterMsecFlagName,
+			Usage:   "Maximum random delay in milliseconds added to each loop, desynchronizing instances sharing RPC providers",
+			EnvVars: opservice.PrefixEnvVar(envVarPrefix, "LOOP_JITTER_MSEC"),
+		},
+		&cli.StringFlag{
+			Name:    LoopIntervalFileFlagName,
+			Usage:   "File holding a loop interval in milliseconds, re-read on SIGHUP to change the interval without a restart",
+			EnvVars: opservice.PrefixEnvVar(envVarPrefix, "LOOP_INTERVAL_FILE"),
+		},
+	)
 }
 
 func (app *cliApp) Start(ctx context.Context) error {
@@ -80,36 +140,165 @@ func (app *cliApp) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start metrics server: %w", err)
 	}
 
-	app.log.Info("starting monitor...", "loop_interval_ms", app.loopIntervalMs)
+	app.log.Info("starting monitor...", "loop_interval_ms", app.loopIntervalMs, "loop_jitter_ms", app.loopJitterMs)
+
+	app.notifyLoopInterval(app.loopIntervalMs)
 
 	// Tick to avoid having to wait a full interval on startup
 	app.monitor.Run(ctx)
 
-	app.worker = clock.NewLoopFn(clock.SystemClock, app.monitor.Run, nil, time.Millisecond*time.Duration(app.loopIntervalMs))
+	app.runCtx, app.runCancel = context.WithCancel(context.Background())
+	app.mu.Lock()
+	app.worker = clock.NewLoopFn(app.clock, app.tick, nil, time.Millisecond*time.Duration(app.loopIntervalMs))
+	app.mu.Unlock()
 	app.metricsSrv = srv
+
+	if app.loopIntervalFile != "" {
+		app.sighup = make(chan os.Signal, 1)
+		signal.Notify(app.sighup, syscall.SIGHUP)
+		go app.reloadOnSighup()
+	}
 	return nil
 }
 
+// tick runs the monitor after a random delay of up to the configured jitter. The jitter
+// is abandoned if the worker is closed, while the monitor runs until the app is stopped.
+func (app *cliApp) tick(ctx context.Context) {
+	if app.loopJitterMs > 0 {
+		jitter := time.Duration(rand.Int63n(int64(app.loopJitterMs))) * time.Millisecond
+		select {
+		case <-ctx.Done():
+			return
+		case <-app.clock.After(jitter):
+		}
+	}
+
+	app.tickMu.Lock()
+	defer app.tickMu.Unlock()
+	if app.runCtx.Err() != nil {
+		return
+	}
+	app.monitor.Run(app.runCtx)
+}
+
+// reloadOnSighup re-reads the loop interval file on every SIGHUP until the app is stopped
+func (app *cliApp) reloadOnSighup() {
+	for {
+		select {
+		case <-app.runCtx.Done():
+			return
+		case <-app.sighup:
+		}
+
+		loopIntervalMs, err := readLoopIntervalFile(app.loopIntervalFile)
+		if err != nil {
+			app.log.Error("failed to read loop interval", "file", app.loopIntervalFile, "err", err)
+			continue
+		}
+		if err := app.setLoopInterval(loopIntervalMs); err != nil {
+			app.log.Error("failed to change loop interval", "loop_interval_ms", loopIntervalMs, "err", err)
+		}
+	}
+}
+
+func readLoopIntervalFile(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// setLoopInterval replaces the worker with one ticking at the new interval. The worker
+// being replaced finishes its in-flight tick, if any, and no progress of the monitor is lost.
+func (app *cliApp) setLoopInterval(loopIntervalMs uint64) error {
+	if loopIntervalMs == 0 {
+		return errors.New("zero loop interval configured")
+	}
+	if app.loopJitterMs >= loopIntervalMs {
+		return errors.New("loop jitter must be less than the loop interval")
+	}
+
+	app.mu.Lock()
+	if app.worker == nil || app.runCtx.Err() != nil {
+		app.mu.Unlock()
+		return errors.New("monitor not running")
+	}
+	if loopIntervalMs == app.loopIntervalMs {
+		app.mu.Unlock()
+		return nil
+	}
+	prev := app.worker
+	app.log.Info("changing loop interval", "from_ms", app.loopIntervalMs, "to_ms", loopIntervalMs)
+	app.loopIntervalMs = loopIntervalMs
+	app.notifyLoopInterval(loopIntervalMs)
+	app.worker = clock.NewLoopFn(app.clock, app.tick, nil, time.Millisecond*time.Duration(loopIntervalMs))
+	app.mu.Unlock()
+
+	return prev.Close()
+}
+
+// notifyLoopInterval passes the loop interval on to the monitor, if it depends on it
+func (app *cliApp) notifyLoopInterval(loopIntervalMs uint64) {
+	if observer, ok := app.monitor.(LoopIntervalObserver); ok {
+		observer.SetLoopInterval(time.Duration(loopIntervalMs) * time.Millisecond)
+	}
+}
+
 func (app *cliApp) Stop(ctx context.Context) error {
 	if app.stopped.Load() {
 		return errors.New("monitor already closed")
 	}
 
 	app.log.Info("closing monitor...")
-	if err := app.worker.Close(); err != nil {
+
+	if app.sighup != nil {
+		signal.Stop(app.sighup)
+	}
+	if app.runCancel != nil {
+		app.runCancel()
+	}
+
+	// Cancel and drain any in-flight tick before the monitor releases its resources
+	if err := app.drainWorker(ctx); err != nil {
 		app.log.Error("error stopping worker loop", "err", err)
 	}
 	if err := app.monitor.Close(ctx); err != nil {
 		app.log.Error("error closing monitor", "err", err)
 	}
-	if err := app.metricsSrv.Close(); err != nil {
-		app.log.Error("error closing metrics server", "err", err)
+	if app.metricsSrv != nil {
+		if err := app.metricsSrv.Close(); err != nil {
+			app.log.Error("error closing metrics server", "err", err)
+		}
 	}
 
 	app.stopped.Store(true)
 	return nil
 }
 
+// drainWorker cancels the context of the in-flight tick and waits for it to return,
+// giving up after the drain timeout or once the stop context is done.
+func (app *cliApp) drainWorker(ctx context.Context) error {
+	app.mu.Lock()
+	worker := app.worker
+	app.mu.Unlock()
+	if worker == nil {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- worker.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, drainTimeout)
+	defer cancel()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for in-flight tick: %w", ctx.Err())
+	}
+}
+
 func (app *cliApp) Stopped() bool {
 	return app.stopped.Load()
 }
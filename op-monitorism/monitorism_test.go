@@ -0,0 +1,78 @@
+package monitorism
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/clock"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+type fakeMonitor struct {
+	runs         chan struct{}
+	loopInterval time.Duration
+}
+
+func (f *fakeMonitor) Run(context.Context) {
+	f.runs <- struct{}{}
+}
+
+func (f *fakeMonitor) Close(context.Context) error {
+	return nil
+}
+
+func (f *fakeMonitor) SetLoopInterval(interval time.Duration) {
+	f.loopInterval = interval
+}
+
+func newTestCliContext(t *testing.T, values map[string]string) *cli.Context {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range DefaultCLIFlags("TEST") {
+		require.NoError(t, f.Apply(fs))
+	}
+	for name, value := range values {
+		require.NoError(t, fs.Set(name, value))
+	}
+	return cli.NewContext(cli.NewApp(), fs, nil)
+}
+
+func TestCliAppLoop(t *testing.T) {
+	ctx := newTestCliContext(t, map[string]string{
+		LoopIntervalMsecFlagName:     "1000",
+		opmetrics.ListenAddrFlagName: "127.0.0.1",
+		opmetrics.PortFlagName:       "0",
+	})
+	clk := clock.NewDeterministicClock(time.Unix(1700000000, 0))
+	monitor := &fakeMonitor{runs: make(chan struct{}, 1)}
+	app, err := NewCliAppWithClock(ctx, testlog.Logger(t, log.LevelDebug), prometheus.NewRegistry(), monitor, clk)
+	require.NoError(t, err)
+
+	// ticks once on start, before the loop
+	require.NoError(t, app.Start(context.Background()))
+	defer func() { require.NoError(t, app.Stop(context.Background())) }()
+	<-monitor.runs
+	require.Equal(t, time.Second, monitor.loopInterval)
+
+	// then on every interval of the clock
+	require.True(t, clk.WaitForNewPendingTaskWithTimeout(time.Second))
+	for i := 0; i < 3; i++ {
+		select {
+		case <-monitor.runs:
+			t.Fatal("ran before the interval elapsed")
+		default:
+		}
+		clk.AdvanceTime(time.Second)
+		select {
+		case <-monitor.runs:
+		case <-time.After(time.Second):
+			t.Fatal("did not run once the interval elapsed")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package withdrawals
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
@@ -10,7 +11,10 @@ import (
 	"github.com/ethereum-optimism/optimism/op-service/metrics"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
@@ -27,6 +31,21 @@ const (
 
 var (
 	WithdrawalProvenEventABIHash = crypto.Keccak256Hash([]byte(WithdrawalProvenEventABI))
+
+	// abi.encode(nonce, sender, target, value, gasLimit, data) preimage of a withdrawal hash
+	withdrawalHashArgs = func() abi.Arguments {
+		uint256Type, _ := abi.NewType("uint256", "", nil)
+		addressType, _ := abi.NewType("address", "", nil)
+		bytesType, _ := abi.NewType("bytes", "", nil)
+		return abi.Arguments{
+			{Name: "nonce", Type: uint256Type},
+			{Name: "sender", Type: addressType},
+			{Name: "target", Type: addressType},
+			{Name: "value", Type: uint256Type},
+			{Name: "gasLimit", Type: uint256Type},
+			{Name: "data", Type: bytesType},
+		}
+	}()
 )
 
 type Monitor struct {
@@ -37,15 +56,19 @@ type Monitor struct {
 
 	optimismPortalAddress common.Address
 	optimismPortal        *bindings.OptimismPortalCaller
-	l2ToL1MP              *bindings.L2ToL1MessagePasserCaller
+	optimismPortalABI     *abi.ABI
 
 	maxBlockRange uint64
 	nextL1Height  uint64
 
+	// withdrawals that could not be matched to an L2 message
+	unprovableWithdrawalHashes map[common.Hash]struct{}
+
 	// metrics
 	highestBlockNumber     *prometheus.GaugeVec
 	isDetectingForgeries   prometheus.Gauge
 	withdrawalsValidated   prometheus.Counter
+	unprovableWithdrawals  prometheus.Counter
 	nodeConnectionFailures *prometheus.CounterVec
 }
 
@@ -65,9 +88,9 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 	if err != nil {
 		return nil, fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
 	}
-	l2ToL1MP, err := bindings.NewL2ToL1MessagePasserCaller(predeploys.L2ToL1MessagePasserAddr, l2Client)
+	optimismPortalABI, err := bindings.OptimismPortalMetaData.GetAbi()
 	if err != nil {
-		return nil, fmt.Errorf("failed to bind to the OptimismPortal: %w", err)
+		return nil, fmt.Errorf("failed to parse the OptimismPortal abi: %w", err)
 	}
 
 	return &Monitor{
@@ -78,11 +101,13 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 
 		optimismPortalAddress: cfg.OptimismPortalAddress,
 		optimismPortal:        optimismPortal,
-		l2ToL1MP:              l2ToL1MP,
+		optimismPortalABI:     optimismPortalABI,
 
 		maxBlockRange: cfg.EventBlockRange,
 		nextL1Height:  cfg.StartingL1BlockHeight,
 
+		unprovableWithdrawalHashes: make(map[common.Hash]struct{}),
+
 		/** Metrics **/
 		isDetectingForgeries: m.NewGauge(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
@@ -94,6 +119,11 @@ func NewMonitor(ctx context.Context, log log.Logger, m metrics.Factory, cfg CLIC
 			Name:      "withdrawalsValidated",
 			Help:      "number of withdrawals successfully validated",
 		}),
+		unprovableWithdrawals: m.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "unprovableWithdrawals",
+			Help:      "number of proven withdrawals that could not be matched to an L2 message",
+		}),
 		highestBlockNumber: m.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: MetricsNamespace,
 			Name:      "highestBlockNumber",
@@ -155,11 +185,27 @@ func (m *Monitor) Run(ctx context.Context) {
 		m.log.Info("checking withdrawal", "withdrawal_hash", withdrawalHash.String(),
 			"block_height", provenWithdrawalLog.BlockNumber, "tx_hash", provenWithdrawalLog.TxHash.String())
 
-		seen, err := m.l2ToL1MP.SentMessages(nil, withdrawalHash)
+		derivedWithdrawalHash, proofSubmitter, err := m.deriveWithdrawalHash(ctx, provenWithdrawalLog)
+		if err != nil {
+			// Return early and loop back into the same block range
+			m.log.Error("failed to query proving transaction", "tx_hash", provenWithdrawalLog.TxHash.String(), "err", err)
+			m.nodeConnectionFailures.WithLabelValues("l1", "transactionByHash").Inc()
+			return
+		}
+		if derivedWithdrawalHash == nil {
+			m.log.Warn("proving transaction does not call the OptimismPortal directly. using the emitted withdrawal hash",
+				"withdrawal_hash", withdrawalHash.String(), "tx_hash", provenWithdrawalLog.TxHash.String())
+			derivedWithdrawalHash = &withdrawalHash
+		} else if *derivedWithdrawalHash != withdrawalHash {
+			m.log.Warn("derived withdrawal hash does not match the emitted withdrawal hash",
+				"withdrawal_hash", withdrawalHash.String(), "derived_withdrawal_hash", derivedWithdrawalHash.String())
+		}
+
+		seen, err := m.isMessageSent(ctx, *derivedWithdrawalHash)
 		if err != nil {
 			// Return early and loop back into the same block range
-			log.Error("failed to query L2ToL1MP sentMessages mapping", "withdrawal_hash", withdrawalHash.String(), "err", err)
-			m.nodeConnectionFailures.WithLabelValues("l2", "sentMessages").Inc()
+			m.log.Error("failed to query L2ToL1MP sentMessages proof", "withdrawal_hash", derivedWithdrawalHash.String(), "err", err)
+			m.nodeConnectionFailures.WithLabelValues("l2", "getProof").Inc()
 			return
 		}
 
@@ -167,7 +213,12 @@ func (m *Monitor) Run(ctx context.Context) {
 		// into a loop at this block range. May want to update this logic such that future
 		// forgeries can be detected -- the existence of one implies many others likely exist.
 		if !seen {
-			m.log.Warn("forgery detected!!!!", "withdrawal_hash", withdrawalHash.String())
+			m.log.Warn("forgery detected!!!!", "withdrawal_hash", derivedWithdrawalHash.String(),
+				"proof_submitter", proofSubmitter.String(), "block_height", provenWithdrawalLog.BlockNumber)
+			if _, ok := m.unprovableWithdrawalHashes[*derivedWithdrawalHash]; !ok {
+				m.unprovableWithdrawalHashes[*derivedWithdrawalHash] = struct{}{}
+				m.unprovableWithdrawals.Inc()
+			}
 			m.isDetectingForgeries.Set(1)
 			return
 		}
@@ -183,6 +234,61 @@ func (m *Monitor) Run(ctx context.Context) {
 	m.highestBlockNumber.WithLabelValues("checked").Set(float64(toBlockNumber))
 }
 
+// deriveWithdrawalHash re-derives the withdrawal hash from the calldata of the transaction that proved it,
+// returning the hash alongside the proof submitter. If the transaction did not call the OptimismPortal
+// directly, the calldata cannot be decoded and a nil hash is returned.
+func (m *Monitor) deriveWithdrawalHash(ctx context.Context, provenWithdrawalLog types.Log) (*common.Hash, common.Address, error) {
+	tx, _, err := m.l1Client.TransactionByHash(ctx, provenWithdrawalLog.TxHash)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to query transaction: %w", err)
+	}
+	proofSubmitter, err := m.l1Client.TransactionSender(ctx, tx, provenWithdrawalLog.BlockHash, provenWithdrawalLog.TxIndex)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to query transaction sender: %w", err)
+	}
+
+	method := m.optimismPortalABI.Methods["proveWithdrawalTransaction"]
+	if tx.To() == nil || *tx.To() != m.optimismPortalAddress || !bytes.HasPrefix(tx.Data(), method.ID) {
+		return nil, proofSubmitter, nil
+	}
+
+	args, err := method.Inputs.Unpack(tx.Data()[len(method.ID):])
+	if err != nil || len(args) == 0 {
+		m.log.Warn("failed to decode proveWithdrawalTransaction calldata", "tx_hash", tx.Hash().String(), "err", err)
+		return nil, proofSubmitter, nil
+	}
+	withdrawal := *abi.ConvertType(args[0], new(bindings.TypesWithdrawalTransaction)).(*bindings.TypesWithdrawalTransaction)
+
+	enc, err := withdrawalHashArgs.Pack(withdrawal.Nonce, withdrawal.Sender, withdrawal.Target, withdrawal.Value, withdrawal.GasLimit, withdrawal.Data)
+	if err != nil {
+		m.log.Warn("failed to encode withdrawal", "tx_hash", tx.Hash().String(), "err", err)
+		return nil, proofSubmitter, nil
+	}
+
+	withdrawalHash := crypto.Keccak256Hash(enc)
+	return &withdrawalHash, proofSubmitter, nil
+}
+
+// isMessageSent checks the `sentMessages` mapping of the L2ToL1MessagePasser for the withdrawal hash
+// using a storage proof of the latest L2 state.
+func (m *Monitor) isMessageSent(ctx context.Context, withdrawalHash common.Hash) (bool, error) {
+	// sentMessages is the first storage slot of the L2ToL1MessagePasser
+	storageKey := crypto.Keccak256Hash(withdrawalHash.Bytes(), common.Hash{}.Bytes())
+
+	proof := struct {
+		StorageProof []struct{ Value *hexutil.Big }
+	}{}
+	if err := m.l2Client.Client().CallContext(ctx, &proof, "eth_getProof",
+		predeploys.L2ToL1MessagePasserAddr, []common.Hash{storageKey}, "latest"); err != nil {
+		return false, err
+	}
+	if len(proof.StorageProof) != 1 || proof.StorageProof[0].Value == nil {
+		return false, fmt.Errorf("unexpected storage proof response for key %s", storageKey.String())
+	}
+
+	return proof.StorageProof[0].Value.ToInt().Sign() != 0, nil
+}
+
 func (m *Monitor) Close(_ context.Context) error {
 	m.l1Client.Close()
 	m.l2Client.Close()
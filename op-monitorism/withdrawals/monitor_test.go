@@ -0,0 +1,118 @@
+package withdrawals
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum-optimism/monitorism/op-monitorism/withdrawals/bindings"
+	"github.com/ethereum-optimism/optimism/op-service/testlog"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+type testRPCRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// newTestRPCServer serves json-rpc requests with the results of respond, by method and params
+func newTestRPCServer(t *testing.T, respond func(method string, params []json.RawMessage) any) *ethclient.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req testRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": respond(req.Method, req.Params)}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := ethclient.Dial(srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestDeriveWithdrawalHashAndIsMessageSent(t *testing.T) {
+	portalABI, err := bindings.OptimismPortalMetaData.GetAbi()
+	require.NoError(t, err)
+	portal := common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed")
+
+	sender, target := common.HexToAddress("0x4200000000000000000000000000000000000007"), common.HexToAddress("0x25ace71c97B33Cc4729CF772ae268934F7ab5fA1")
+	withdrawal := bindings.TypesWithdrawalTransaction{
+		Nonce:    new(big.Int).SetBytes(common.FromHex("0x00010000000000000000000000000000000000000000000000000000000003e8")),
+		Sender:   sender,
+		Target:   target,
+		Value:    big.NewInt(1_000_000_000_000_000_000),
+		GasLimit: big.NewInt(200_000),
+		Data:     common.FromHex("0xd764ad0b"),
+	}
+
+	// the withdrawal hash and sentMessages slot, as derived by the op-node's crossdomain.Withdrawal
+	// and withdrawals.StorageSlotOfWithdrawalHash
+	expectedHash := common.HexToHash("0x003286e591e3cbb193e8a964430bd7212ee56b0bac2da91472f4ffe24209b3e6")
+	expectedSlot := common.HexToHash("0x77d60cd2ef18825e0ebc28c45e09f1f18fd7f551a659700dbeca3424d7d543e8")
+
+	calldata, err := portalABI.Pack("proveWithdrawalTransaction", withdrawal, big.NewInt(1), bindings.TypesOutputRootProof{}, [][]byte{})
+	require.NoError(t, err)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{To: &portal, Gas: 500_000, GasPrice: big.NewInt(1), Data: calldata})
+	require.NoError(t, err)
+	provenLog := types.Log{TxHash: tx.Hash(), BlockHash: common.Hash{0x1}, TxIndex: 0}
+
+	l1Client := newTestRPCServer(t, func(method string, _ []json.RawMessage) any {
+		require.Equal(t, "eth_getTransactionByHash", method)
+		fields := make(map[string]any)
+		enc, err := tx.MarshalJSON()
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(enc, &fields))
+		fields["blockHash"], fields["blockNumber"], fields["transactionIndex"] = provenLog.BlockHash, "0x1", "0x0"
+		fields["from"] = crypto.PubkeyToAddress(key.PublicKey)
+		return fields
+	})
+	l2Client := newTestRPCServer(t, func(method string, params []json.RawMessage) any {
+		require.Equal(t, "eth_getProof", method)
+		var keys []common.Hash
+		require.NoError(t, json.Unmarshal(params[1], &keys))
+		require.Len(t, keys, 1)
+
+		value := new(big.Int)
+		if keys[0] == expectedSlot {
+			value.SetUint64(1)
+		}
+		return map[string]any{"storageProof": []map[string]any{{"key": keys[0], "value": (*hexutil.Big)(value), "proof": []string{}}}}
+	})
+
+	m := &Monitor{
+		log:                   testlog.Logger(t, log.LevelDebug),
+		l1Client:              l1Client,
+		l2Client:              l2Client,
+		optimismPortalAddress: portal,
+		optimismPortalABI:     portalABI,
+	}
+
+	hash, proofSubmitter, err := m.deriveWithdrawalHash(context.Background(), provenLog)
+	require.NoError(t, err)
+	require.NotNil(t, hash)
+	require.Equal(t, expectedHash, *hash)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), proofSubmitter)
+
+	// sent at the slot of the withdrawal hash, and not at the slot of another hash
+	sent, err := m.isMessageSent(context.Background(), *hash)
+	require.NoError(t, err)
+	require.True(t, sent)
+	sent, err = m.isMessageSent(context.Background(), common.Hash{0x1})
+	require.NoError(t, err)
+	require.False(t, sent)
+}